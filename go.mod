@@ -3,6 +3,8 @@ module github.com/etacticsinc/terraform-provider-ldap
 go 1.14
 
 require (
+	github.com/go-asn1-ber/asn1-ber v1.5.1
 	github.com/go-ldap/ldap/v3 v3.2.2
 	github.com/hashicorp/terraform-plugin-sdk v1.15.0
+	golang.org/x/crypto v0.0.0-20200604202706-70a84ac30bf9
 )
@@ -0,0 +1,96 @@
+package ldap
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"strings"
+)
+
+func ignoreAttributeChangesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeSet,
+		Optional:      true,
+		Elem:          &schema.Schema{Type: schema.TypeString},
+		Set:           schema.HashString,
+		ConflictsWith: []string{"managed_attributes"},
+		Description:   "Specifies LDAP attributes whose values on the server are not written back to state when the object is read.",
+	}
+}
+
+func managedAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeSet,
+		Optional:      true,
+		Elem:          &schema.Schema{Type: schema.TypeString},
+		Set:           schema.HashString,
+		ConflictsWith: []string{"ignore_attribute_changes"},
+		Description:   "Specifies the only LDAP attributes whose values on the server are written back to state when the object is read.",
+	}
+}
+
+// attributeFilter wraps an Object so that SetAttributes only applies server values for tracked
+// attributes, keeping the object's current values for the rest.
+type attributeFilter struct {
+	Object
+	current Attributes
+	tracked func(key string) bool
+}
+
+// newAttributeFilter returns obj wrapped according to the ignore_attribute_changes and
+// managed_attributes arguments of d, or obj itself when neither is configured.
+func newAttributeFilter(obj Object, d *schema.ResourceData) Object {
+	ignored := attributeNames(d.Get("ignore_attribute_changes"))
+	managed := attributeNames(d.Get("managed_attributes"))
+	if len(ignored) == 0 && len(managed) == 0 {
+		return obj
+	}
+	return &attributeFilter{
+		Object:  obj,
+		current: obj.GetAttributes(),
+		tracked: func(key string) bool {
+			if len(managed) > 0 {
+				return containsFold(managed, key)
+			}
+			return !containsFold(ignored, key)
+		},
+	}
+}
+
+func (f *attributeFilter) SetAttributes(attributes Attributes) {
+	m := make(map[string][]string)
+	for key, elem := range f.current.Map {
+		m[key] = elem
+	}
+	for key, elem := range attributes.Map {
+		if f.tracked(key) {
+			if match, ok := f.current.Lookup(key); ok {
+				delete(m, match)
+			}
+			m[key] = elem
+		}
+	}
+	for key := range f.current.Map {
+		if _, ok := attributes.Lookup(key); !ok && f.tracked(key) {
+			m[key] = nil
+		}
+	}
+	f.Object.SetAttributes(Attributes{m})
+}
+
+func attributeNames(v interface{}) []string {
+	names := make([]string, 0)
+	if set, ok := v.(*schema.Set); ok {
+		for _, name := range set.List() {
+			names = append(names, name.(string))
+		}
+	}
+	return names
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
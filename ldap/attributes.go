@@ -3,6 +3,7 @@ package ldap
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 type Attributes struct {
@@ -44,10 +45,45 @@ func (a *Attributes) Keys() []string {
 	return keys
 }
 
+// Lookup returns the key matching name, ignoring case as LDAP attribute descriptions do.
+func (a *Attributes) Lookup(name string) (string, bool) {
+	if _, ok := a.Map[name]; ok {
+		return name, true
+	}
+	for key := range a.Map {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// Merge copies the attributes of other whose keys are not already present.
+func (a *Attributes) Merge(other Attributes) {
+	for key, elem := range other.Map {
+		if _, ok := a.Lookup(key); !ok {
+			a.Map[key] = elem
+		}
+	}
+}
+
+// Select returns the values of the given keys, preserving the casing of keys.
+func (a *Attributes) Select(keys []string) Attributes {
+	m := make(map[string][]string)
+	for _, key := range keys {
+		if match, ok := a.Lookup(key); ok {
+			m[key] = a.Map[match]
+		} else {
+			m[key] = nil
+		}
+	}
+	return Attributes{m}
+}
+
 func (a *Attributes) String() string {
 	m := make(map[string][]string)
 	a.ForEach(func(key string, value []string) {
-		m[key] = value
+		m[key] = redact(key, value)
 	})
 	json, _ := json.MarshalIndent(m, "", "  ")
 	return fmt.Sprintf("%v", string(json))
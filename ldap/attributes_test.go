@@ -0,0 +1,78 @@
+package ldap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAttributes_Get(t *testing.T) {
+	attributes := Attributes{map[string][]string{
+		"cn":          {"admins"},
+		"description": {""},
+		"member":      {"uid=alice,dc=example,dc=com", "uid=bob,dc=example,dc=com"},
+		"memberUid":   nil,
+	}}
+	if actual := attributes.Get("member"); len(actual) != 2 {
+		t.Errorf("Get(member) = %q, expected 2 values", actual)
+	}
+	if actual := attributes.GetFirst("member"); actual != "uid=alice,dc=example,dc=com" {
+		t.Errorf("GetFirst(member) = %q, expected the first value", actual)
+	}
+	if actual := attributes.GetFirst("memberUid"); actual != "" {
+		t.Errorf("GetFirst(memberUid) = %q, expected \"\"", actual)
+	}
+	for key, expected := range map[string]bool{"cn": true, "description": false, "memberUid": false, "uid": false} {
+		if actual := attributes.HasValue(key); actual != expected {
+			t.Errorf("HasValue(%s) = %t, expected %t", key, actual, expected)
+		}
+	}
+	var keys []string
+	attributes.ForEach(func(key string, _ []string) {
+		keys = append(keys, key)
+	})
+	if len(keys) != 2 {
+		t.Errorf("ForEach visited %q, expected cn and member", keys)
+	}
+}
+
+func TestAttributes_Lookup(t *testing.T) {
+	attributes := Attributes{map[string][]string{"sAMAccountName": {"alice"}}}
+	for _, name := range []string{"sAMAccountName", "samaccountname", "SAMACCOUNTNAME"} {
+		if key, ok := attributes.Lookup(name); !ok || key != "sAMAccountName" {
+			t.Errorf("Lookup(%s) = %q, %t, expected \"sAMAccountName\", true", name, key, ok)
+		}
+	}
+	if key, ok := attributes.Lookup("uid"); ok {
+		t.Errorf("Lookup(uid) = %q, %t, expected \"\", false", key, ok)
+	}
+}
+
+func TestAttributes_Merge(t *testing.T) {
+	attributes := Attributes{map[string][]string{"cn": {"admins"}, "description": {"Administrators"}}}
+	attributes.Merge(Attributes{map[string][]string{"Description": {"Ignored"}, "gidNumber": {"5000"}}})
+	expected := map[string][]string{"cn": {"admins"}, "description": {"Administrators"}, "gidNumber": {"5000"}}
+	if !reflect.DeepEqual(attributes.Map, expected) {
+		t.Errorf("Merge = %q, expected %q", attributes.Map, expected)
+	}
+}
+
+func TestAttributes_Select(t *testing.T) {
+	attributes := Attributes{map[string][]string{"cn": {"admins"}, "gidnumber": {"5000"}}}
+	selected := attributes.Select([]string{"gidNumber", "description"})
+	expected := map[string][]string{"gidNumber": {"5000"}, "description": nil}
+	if !reflect.DeepEqual(selected.Map, expected) {
+		t.Errorf("Select = %q, expected %q", selected.Map, expected)
+	}
+}
+
+func TestAttributes_String(t *testing.T) {
+	attributes := Attributes{map[string][]string{"uid": {"alice"}, "userPassword": {"secret"}}}
+	actual := attributes.String()
+	if strings.Contains(actual, "secret") {
+		t.Errorf("String = %s, expected userPassword to be redacted", actual)
+	}
+	if !strings.Contains(actual, "alice") || !strings.Contains(actual, "(redacted)") {
+		t.Errorf("String = %s, expected uid and a redacted userPassword", actual)
+	}
+}
@@ -5,44 +5,186 @@ import (
 	"fmt"
 	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
 	"github.com/go-ldap/ldap/v3"
+	"log"
+	"net"
+	url2 "net/url"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
+const (
+	controlTypeTreeDelete   = "1.2.840.113556.1.4.805"
+	searchPageSize          = 500
+	defaultWriteTimeout     = 5 * time.Minute
+	retryBaseDelay          = time.Second
+	retryMaxDelay           = 30 * time.Second
+	consistencyPollInterval = time.Second
+)
+
+// errStale is returned by a read that has not yet observed a write made earlier in the same session.
+var errStale = errors.New("the entry has not yet replicated to the server that was read")
+
 type Client struct {
-	Server       string
-	BindDN       string
-	BindPassword string
+	Server             string
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	ConnectTimeout     time.Duration
+	ConsistencyTimeout time.Duration
+	Flavor             string
+	GlobalCatalog      string
+	LDIFOutputFile     string
+	MaxRetries         int
+	ReadOnly           bool
+	RequestTimeout     time.Duration
+	RootDSE            Attributes
+	ValidateSchema     bool
+
+	deadline time.Time
+	session  *session
+	shared   *clientShared
+}
+
+// clientShared is the state shared by a client and the copies returned by WithTimeout.
+type clientShared struct {
+	ldifMutex      sync.Mutex
+	subschema      *Subschema
+	subschemaMutex sync.Mutex
+}
+
+// session is a connection reused by the requests of a pinned client, so that its reads are served by
+// the server that made its writes even when the server name resolves to several replicas.
+type session struct {
+	conn    *ldap.Conn
+	mutex   sync.Mutex
+	written map[string]sessionWrite
+}
+
+// sessionWrite records the values written to an entry and the connection that wrote them.
+type sessionWrite struct {
+	conn       *ldap.Conn
+	attributes Attributes
+}
+
+// Pin returns a copy of the client whose requests share one connection until Close is called.
+func (c *Client) Pin() *Client {
+	client := *c
+	client.session = &session{written: make(map[string]sessionWrite)}
+	return &client
+}
+
+// Close closes the connection of a pinned client.
+func (c *Client) Close() {
+	if c.session == nil {
+		return
+	}
+	c.session.mutex.Lock()
+	defer c.session.mutex.Unlock()
+	if c.session.conn != nil {
+		c.session.conn.Close()
+		c.session.conn = nil
+	}
+}
+
+// globalCatalog returns an unpinned copy of the client connected to the Global Catalog, which holds
+// every domain of an Active Directory forest. Unless GlobalCatalog is set, it is the server on port 3268,
+// or 3269 for ldaps.
+func (c *Client) globalCatalog() (*Client, error) {
+	server := c.GlobalCatalog
+	if server == "" {
+		url, err := url2.Parse(c.Server)
+		if err != nil {
+			return nil, err
+		}
+		port := "3268"
+		if strings.EqualFold(url.Scheme, "ldaps") {
+			port = "3269"
+		}
+		url.Host = net.JoinHostPort(url.Hostname(), port)
+		server = url.String()
+	}
+	client := *c
+	client.Server = server
+	client.session = nil
+	return &client, nil
+}
+
+// WithTimeout returns a copy of the client whose requests and retries stop once timeout has elapsed.
+func (c *Client) WithTimeout(timeout time.Duration) *Client {
+	client := *c
+	client.deadline = time.Now().Add(timeout)
+	return &client
 }
 
 func (c *Client) Add(obj Object) error {
+	dn := obj.GetRelativeDN()
+	if path := c.ResolvePath(obj.GetPath()); path != "" {
+		dn = fmt.Sprintf("%s,%s", dn, path)
+	}
 	add := func(conn *ldap.Conn) error {
-		dn := fmt.Sprintf("%s,%s", obj.GetRelativeDN(), obj.GetPath())
+		obj.SetDN(dn) // Attributes such as a placeholder member may refer to the new entry
 		attributes := obj.GetAttributes()
 		request := ldap.NewAddRequest(dn, []ldap.Control{})
 		attributes.ForEach(request.Attribute)
-		if err := conn.Add(request); err != nil {
-			return fmt.Errorf("%v\nattributes: %v", err, attributes.String())
+		if err := c.checkWritable("add", dn, ldifAddRecord(request)); err != nil {
+			return err
 		}
-		return nil
+		if err := c.add(conn, request); err != nil {
+			return c.newError("add", dn, err, append([]string{"attributes:"}, describeAttributes(attributes)...)...)
+		}
+		c.recordWrite(conn, dn, attributes)
+		return c.writeLDIF(ldifAddRecord(request))
 	}
-	return c.bindThen(add)
+	return c.writeThen(add)
 }
 
 func (c *Client) Search(obj Object) error {
-	search := func(conn *ldap.Conn) error {
-		path := obj.GetPath()
-		filter := internal.Filter(obj.GetRelativeDN(), obj.GetObjectClass())
+	search := func(conn *ldap.Conn, wait bool) error {
+		// Search the entry itself first, since the path of a naming context root may not exist
+		path := obj.GetRelativeDN()
+		if parent := c.ResolvePath(obj.GetPath()); parent != "" {
+			path = fmt.Sprintf("%s,%s", path, parent)
+		}
+		if obj.GetDN() != "" {
+			path = obj.GetDN()
+		}
+		assertion := "objectClass=*" // The naming value is read from the entry on import
+		attribute, value := obj.GetNamingAttribute()
+		if value != "" {
+			assertion = attribute + "=" + ldap.EscapeFilter(value)
+		}
+		filter := internal.Filter(assertion, obj.GetObjectClass())
 		attributes := obj.GetAttributes()
-		request := ldap.NewSearchRequest(path, ldap.ScopeWholeSubtree, 0, 0, 0, false, filter, attributes.Keys(), []ldap.Control{})
-		result, err := conn.Search(request)
+		request := ldap.NewSearchRequest(path, ldap.ScopeBaseObject, 0, 0, 0, false, filter, attributes.Keys(), []ldap.Control{})
+		result, err := c.search(conn, request)
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			result, err = &ldap.SearchResult{}, nil
+		}
 		if err != nil {
-			return fmt.Errorf("%s\nserver: %s\nsearch base: %s\nfilter: %s", err, c.Server, path, filter)
+			return c.newError("search", path, err, "filter: "+filter)
 		}
 		entries := result.Entries
+		if wait && len(entries) < 2 && c.stale(conn, path, entryAttributes(entries)) {
+			return errStale
+		}
+		if parent := c.ResolvePath(obj.GetPath()); len(entries) == 0 && value != "" && parent != "" {
+			// Follow an entry moved beneath its path outside of Terraform
+			path = parent
+			request := ldap.NewSearchRequest(path, ldap.ScopeWholeSubtree, 0, 0, 0, false, filter, attributes.Keys(), []ldap.Control{})
+			result, err := c.search(conn, request)
+			if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+				return c.newError("search", path, err, "scope: subtree", "filter: "+filter)
+			}
+			if err == nil {
+				entries = result.Entries
+			}
+		}
 		if len(entries) == 0 { // Not found
-			return errors.New(fmt.Sprintf("Resource not found.\nserver: %s\nsearch base: %s\nfilter: %s", c.Server, path, filter))
-		} else if len(entries) > 1 { // Non-unique (shouldn't be possible)
-			return errors.New(fmt.Sprintf("Non-unique search result.\nserver: %s\nsearch base: %s\nfilter: %s", c.Server, path, filter))
+			return c.newError("search", path, ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("Resource not found.")), "filter: "+filter)
+		} else if len(entries) > 1 { // Non-unique, when entries beneath the path share the name
+			return fmt.Errorf("Non-unique search result.\nserver: %s\nsearch base: %s\nfilter: %s", c.Server, path, filter)
 		}
 		m := make(map[string][]string)
 		entry := entries[0]
@@ -53,16 +195,111 @@ func (c *Client) Search(obj Object) error {
 		obj.SetAttributes(Attributes{m})
 		return nil
 	}
-	return c.bindThen(search)
+	return c.readThen(search)
 }
 
 func (c *Client) Delete(obj Object) error {
 	delete := func(conn *ldap.Conn) error {
 		dn := obj.GetDN()
 		request := ldap.NewDelRequest(dn, []ldap.Control{})
-		return conn.Del(request)
+		if err := c.checkWritable("delete", dn, ldifDeleteRecord(request)); err != nil {
+			return err
+		}
+		if err := c.del(conn, request); err != nil {
+			return c.newError("delete", dn, err)
+		}
+		return c.writeLDIF(ldifDeleteRecord(request))
+	}
+	return c.writeThen(delete)
+}
+
+// ResolvePath qualifies path with the base DN unless it already ends with the base DN or one of the
+// server's naming contexts, or is itself the parent of one (e.g. "dc=com").
+func (c *Client) ResolvePath(path string) string {
+	if c.BaseDN == "" {
+		return path
+	}
+	dn, err := ldap.ParseDN(path)
+	if err != nil {
+		return path
 	}
-	return c.bindThen(delete)
+	for _, suffix := range append([]string{c.BaseDN}, c.RootDSE.Get("namingContexts")...) {
+		if suffixDN, err := ldap.ParseDN(suffix); err == nil && (dnEndsWith(dn, suffixDN) || dnEndsWith(suffixDN, dn)) {
+			return path
+		}
+	}
+	return path + "," + c.BaseDN
+}
+
+// DeleteTree deletes obj and all of its descendants, using the Tree Delete control when the server
+// supports it and deleting depth-first otherwise.
+func (c *Client) DeleteTree(obj Object) error {
+	treeDelete := false
+	for _, control := range c.RootDSE.Get("supportedControl") {
+		if control == controlTypeTreeDelete {
+			treeDelete = true
+		}
+	}
+	delete := func(conn *ldap.Conn) error {
+		if treeDelete {
+			control := ldap.NewControlString(controlTypeTreeDelete, true, "")
+			request := ldap.NewDelRequest(obj.GetDN(), []ldap.Control{control})
+			if err := c.checkWritable("delete", obj.GetDN(), ldifDeleteRecord(request)); err != nil {
+				return err
+			}
+			if err := c.del(conn, request); err != nil {
+				return c.newError("tree delete", obj.GetDN(), err)
+			}
+			return c.writeLDIF(ldifDeleteRecord(request))
+		}
+		return c.deleteDepthFirst(conn, obj.GetDN())
+	}
+	return c.writeThen(delete)
+}
+
+// Children returns the DNs of the immediate children of dn.
+func (c *Client) Children(dn string) ([]string, error) {
+	children := make([]string, 0)
+	search := func(conn *ldap.Conn) error {
+		var err error
+		children, err = c.children(conn, dn)
+		return err
+	}
+	return children, c.bindThen(search)
+}
+
+func (c *Client) children(conn *ldap.Conn, dn string) ([]string, error) {
+	request := ldap.NewSearchRequest(dn, ldap.ScopeSingleLevel, 0, 0, 0, false, "(objectClass=*)", []string{"1.1"}, []ldap.Control{})
+	result, err := c.searchWithPaging(conn, request, searchPageSize)
+	if err != nil {
+		return nil, c.newError("search", dn, err, "scope: one level")
+	}
+	children := make([]string, len(result.Entries))
+	for i, entry := range result.Entries {
+		children[i] = entry.DN
+	}
+	sort.Strings(children)
+	return children, nil
+}
+
+func (c *Client) deleteDepthFirst(conn *ldap.Conn, dn string) error {
+	children, err := c.children(conn, dn)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := c.deleteDepthFirst(conn, child); err != nil {
+			return err
+		}
+	}
+	request := ldap.NewDelRequest(dn, []ldap.Control{})
+	if err := c.checkWritable("delete", dn, ldifDeleteRecord(request)); err != nil {
+		return err
+	}
+	if err := c.del(conn, request); err != nil {
+		return c.newError("delete", dn, err)
+	}
+	return c.writeLDIF(ldifDeleteRecord(request))
 }
 
 func (c *Client) Modify(old Object, new Object) error {
@@ -74,8 +311,14 @@ func (c *Client) Modify(old Object, new Object) error {
 				newPath = ""
 			}
 			request := ldap.NewModifyDNRequest(old.GetDN(), new.GetRelativeDN(), true, newPath)
-			if err := conn.ModifyDN(request); err != nil {
-				return errors.New(fmt.Sprintf("%vdn: %v\nrdn: %v\npath: %v", err, old.GetDN(), new.GetRelativeDN(), newPath))
+			if err := c.checkWritable("modify DN", old.GetDN(), ldifModifyDNRecord(request)); err != nil {
+				return err
+			}
+			if err := c.modifyDN(conn, request); err != nil {
+				return c.newError("modify DN", old.GetDN(), err, "new RDN: "+new.GetRelativeDN(), "new superior: "+newPath)
+			}
+			if err := c.writeLDIF(ldifModifyDNRecord(request)); err != nil {
+				return err
 			}
 		}
 		oldAttributes := old.GetAttributes()
@@ -88,9 +331,13 @@ func (c *Client) Modify(old Object, new Object) error {
 				oldAttribute := oldAttributes.Get(key)
 				if oldAttribute == nil {
 					request.Add(key, newAttribute)
+					modified = true
+				} else if len(oldAttribute) != len(newAttribute) {
+					request.Replace(key, newAttribute)
+					modified = true
 				} else {
 					for i, value := range newAttribute {
-						if i >= len(oldAttribute) || oldAttribute[i] != value {
+						if oldAttribute[i] != value {
 							request.Replace(key, newAttribute)
 							modified = true
 							break
@@ -106,32 +353,373 @@ func (c *Client) Modify(old Object, new Object) error {
 			}
 		}
 		if modified {
-			err := conn.Modify(request)
+			if err := c.checkWritable("modify", new.GetDN(), ldifModifyRecord(request)); err != nil {
+				return err
+			}
+			err := c.modify(conn, request)
 			if err != nil {
-				return fmt.Errorf("%vattributes: %v", err, newAttributes.String())
+				return c.newError("modify", new.GetDN(), err, append([]string{"changes:"}, describeChanges(request)...)...)
+			}
+			c.recordWrite(conn, new.GetDN(), newAttributes)
+			return c.writeLDIF(ldifModifyRecord(request))
+		}
+		return nil
+	}
+	return c.writeThen(modify)
+}
+
+// SearchEntries returns the requested attributes of every entry matching filter beneath base, keyed by DN.
+// Results are requested in pages so that server size limits, such as 1000 entries in Active Directory, do not apply.
+func (c *Client) SearchEntries(base string, filter string, attributes []string) (map[string]Attributes, error) {
+	entries := make(map[string]Attributes)
+	search := func(conn *ldap.Conn) error {
+		request := ldap.NewSearchRequest(base, ldap.ScopeWholeSubtree, 0, 0, 0, false, filter, attributes, []ldap.Control{})
+		result, err := c.searchWithPaging(conn, request, searchPageSize)
+		if err != nil {
+			return c.newError("search", base, err, "scope: subtree", "filter: "+filter)
+		}
+		for _, entry := range result.Entries {
+			m := make(map[string][]string)
+			for _, attr := range entry.Attributes {
+				m[attr.Name] = attr.Values
+			}
+			entries[entry.DN] = Attributes{m}
+		}
+		return nil
+	}
+	return entries, c.bindThen(search)
+}
+
+// ReadEntry returns the requested attributes of the entry at dn, or nil when it does not exist.
+func (c *Client) ReadEntry(dn string, attributes []string) (*Attributes, error) {
+	var entry *Attributes
+	search := func(conn *ldap.Conn, wait bool) error {
+		request := ldap.NewSearchRequest(dn, ldap.ScopeBaseObject, 0, 0, 0, false, "(objectClass=*)", attributes, []ldap.Control{})
+		result, err := c.search(conn, request)
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			result, err = &ldap.SearchResult{}, nil
+		}
+		if err != nil {
+			return c.newError("search", dn, err)
+		}
+		entry = entryAttributes(result.Entries)
+		if wait && c.stale(conn, dn, entry) {
+			return errStale
+		}
+		return nil
+	}
+	return entry, c.readThen(search)
+}
+
+// ReadRootDSE returns the requested attributes of the server's root DSE.
+func (c *Client) ReadRootDSE(attributes ...string) (Attributes, error) {
+	m := make(map[string][]string)
+	search := func(conn *ldap.Conn) error {
+		request := ldap.NewSearchRequest("", ldap.ScopeBaseObject, 0, 0, 0, false, "(objectClass=*)", attributes, []ldap.Control{})
+		result, err := c.search(conn, request)
+		if err != nil {
+			return c.newError("search", "", err, "search base: (root DSE)")
+		}
+		if len(result.Entries) > 0 {
+			for _, attr := range result.Entries[0].Attributes {
+				m[attr.Name] = attr.Values
 			}
 		}
 		return nil
 	}
-	return c.bindThen(modify)
+	return Attributes{m}, c.bindThen(search)
+}
+
+// Subschema returns the server's subschema, which is read on first use.
+func (c *Client) Subschema() (*Subschema, error) {
+	c.shared.subschemaMutex.Lock()
+	defer c.shared.subschemaMutex.Unlock()
+	if c.shared.subschema != nil {
+		return c.shared.subschema, nil
+	}
+	dn := c.RootDSE.GetFirst("subschemaSubentry")
+	if dn == "" {
+		dn = "cn=Subschema"
+	}
+	m := make(map[string][]string)
+	search := func(conn *ldap.Conn) error {
+		request := ldap.NewSearchRequest(dn, ldap.ScopeBaseObject, 0, 0, 0, false, "(objectClass=subschema)", []string{"attributeTypes", "objectClasses", "dITContentRules"}, []ldap.Control{})
+		result, err := c.search(conn, request)
+		if err != nil {
+			return c.newError("search", dn, err, "filter: (objectClass=subschema)")
+		}
+		if len(result.Entries) == 0 {
+			return fmt.Errorf("subschema not found\nserver: %s\nsearch base: %s", c.Server, dn)
+		}
+		for _, attr := range result.Entries[0].Attributes {
+			m[attr.Name] = attr.Values
+		}
+		return nil
+	}
+	if err := c.bindThen(search); err != nil {
+		return nil, err
+	}
+	subschema, err := NewSubschema(Attributes{m})
+	if err != nil {
+		return nil, err
+	}
+	c.shared.subschema = subschema
+	return subschema, nil
+}
+
+// AddValues adds values to an attribute of dn without replacing its existing values.
+func (c *Client) AddValues(dn string, key string, values []string) error {
+	add := func(conn *ldap.Conn) error {
+		request := ldap.NewModifyRequest(dn, []ldap.Control{})
+		request.Add(key, values)
+		if err := c.checkWritable("modify", dn, ldifModifyRecord(request)); err != nil {
+			return err
+		}
+		if err := c.modify(conn, request); err != nil {
+			return c.newError("modify", dn, err, append([]string{"changes:"}, describeChanges(request)...)...)
+		}
+		c.recordWrite(conn, dn, Attributes{map[string][]string{key: values}})
+		return c.writeLDIF(ldifModifyRecord(request))
+	}
+	return c.writeThen(add)
+}
+
+// DeleteValues removes values from an attribute of dn, ignoring values that are already absent.
+func (c *Client) DeleteValues(dn string, key string, values []string) error {
+	delete := func(conn *ldap.Conn) error {
+		request := ldap.NewModifyRequest(dn, []ldap.Control{})
+		request.Delete(key, values)
+		if err := c.checkWritable("modify", dn, ldifModifyRecord(request)); err != nil {
+			return err
+		}
+		if err := c.modify(conn, request); err != nil {
+			if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchAttribute) {
+				return nil
+			}
+			return c.newError("modify", dn, err, append([]string{"changes:"}, describeChanges(request)...)...)
+		}
+		return c.writeLDIF(ldifModifyRecord(request))
+	}
+	return c.writeThen(delete)
+}
+
+// Apply performs an LDIF modify, modrdn, moddn or delete change record.
+func (c *Client) Apply(record internal.LDIFRecord) error {
+	if record.ChangeType == "delete" {
+		return c.Delete(&Entry{DN: record.DN})
+	}
+	apply := func(conn *ldap.Conn) error {
+		if record.ChangeType != "modify" {
+			request := ldap.NewModifyDNRequest(record.DN, record.NewRDN, record.DeleteOldRDN, record.NewSuperior)
+			if err := c.checkWritable("modify DN", record.DN, ldifModifyDNRecord(request)); err != nil {
+				return err
+			}
+			if err := c.modifyDN(conn, request); err != nil {
+				return c.newError("modify DN", record.DN, err, "new RDN: "+record.NewRDN, "new superior: "+record.NewSuperior)
+			}
+			return c.writeLDIF(ldifModifyDNRecord(request))
+		}
+		request := ldap.NewModifyRequest(record.DN, []ldap.Control{})
+		for _, modification := range record.Modifications {
+			switch modification.Operation {
+			case "add":
+				request.Add(modification.Name, modification.Values)
+			case "delete":
+				request.Delete(modification.Name, modification.Values)
+			case "replace":
+				request.Replace(modification.Name, modification.Values)
+			case "increment":
+				request.Increment(modification.Name, modification.Values[0])
+			}
+		}
+		if err := c.checkWritable("modify", record.DN, ldifModifyRecord(request)); err != nil {
+			return err
+		}
+		if err := c.modify(conn, request); err != nil {
+			return c.newError("modify", record.DN, err, append([]string{"changes:"}, describeChanges(request)...)...)
+		}
+		return c.writeLDIF(ldifModifyRecord(request))
+	}
+	return c.writeThen(apply)
+}
+
+// checkWritable returns an error describing the attempted operation when the client is read-only, after
+// appending its change record to the LDIF output file, marked as not applied.
+func (c *Client) checkWritable(operation string, dn string, record string) error {
+	if c.ReadOnly {
+		if err := c.appendLDIF("not applied: read_only = true", record); err != nil {
+			return fmt.Errorf("the change could not be written to the LDIF output file: %v", err)
+		}
+		return fmt.Errorf("refusing to %s %q: the provider is configured with read_only = true\nserver: %s", operation, dn, c.Server)
+	}
+	return nil
 }
 
+// bindThen connects and binds to the server and calls fn, which only reads, retrying with exponential
+// backoff when any step fails with a transient result code.
 func (c *Client) bindThen(fn func(*ldap.Conn) error) error {
+	return c.retry(fn, false)
+}
+
+// writeThen calls fn like bindThen, but only retries when connecting or binding fails. A write whose
+// connection dropped or timed out may still have been performed, so repeating it could fail, e.g. with
+// Entry Already Exists, although the write succeeded.
+func (c *Client) writeThen(fn func(*ldap.Conn) error) error {
+	return c.retry(fn, true)
+}
+
+func (c *Client) retry(fn func(*ldap.Conn) error, write bool) error {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		sent, err := c.bindOnceThen(fn)
+		if err == nil || attempt >= c.MaxRetries || !isRetryable(err) || write && sent {
+			return err
+		}
+		if !c.deadline.IsZero() && time.Now().Add(delay).After(c.deadline) {
+			return err
+		}
+		log.Printf("[WARN] LDAP request to %s failed (attempt %d of %d), retrying in %s: %v", c.Server, attempt+1, c.MaxRetries+1, delay, err)
+		time.Sleep(delay)
+		if delay *= 2; delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+	}
+}
+
+// bindOnceThen connects and binds to the server, or reuses the connection of the session, and calls fn.
+// sent reports whether fn was called.
+func (c *Client) bindOnceThen(fn func(*ldap.Conn) error) (sent bool, err error) {
+	timeout := c.RequestTimeout
+	if !c.deadline.IsZero() {
+		remaining := time.Until(c.deadline)
+		if remaining <= 0 {
+			return false, c.newError("connect", "", ldap.NewError(ldap.LDAPResultTimeout, errors.New("the operation timed out before connecting")))
+		}
+		if timeout == 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+	if c.session == nil {
+		conn, err := c.dial(timeout)
+		if err != nil {
+			return false, err
+		}
+		defer conn.Close()
+		return true, fn(conn)
+	}
+	c.session.mutex.Lock()
+	defer c.session.mutex.Unlock()
+	if c.session.conn == nil || c.session.conn.IsClosing() {
+		conn, err := c.dial(timeout)
+		if err != nil {
+			return false, err
+		}
+		c.session.conn = conn
+	}
+	c.session.conn.SetTimeout(timeout)
+	err = fn(c.session.conn)
+	if isRetryable(err) { // Reconnect, possibly to another replica, if the request is retried
+		c.session.conn.Close()
+		c.session.conn = nil
+	}
+	return true, err
+}
+
+func (c *Client) dial(timeout time.Duration) (*ldap.Conn, error) {
 	// Connect to LDAP server
-	conn, err := ldap.DialURL(c.Server)
+	start := time.Now()
+	conn, err := ldap.DialURL(c.Server, ldap.DialWithDialer(&net.Dialer{Timeout: c.ConnectTimeout}))
+	c.logRequest("connect", start, err)
 	if err != nil {
-		return err
+		return nil, c.newError("connect", "", err)
 	}
-	defer conn.Close()
+	conn.SetTimeout(timeout)
 	// Perform bind
-	if c.BindPassword != "" {
-		if err := conn.Bind(c.BindDN, c.BindPassword); err != nil {
+	if err := c.bind(conn); err != nil {
+		conn.Close()
+		return nil, c.newError("bind", c.BindDN, err)
+	}
+	return conn, nil
+}
+
+// readThen calls fn like bindThen, repeating it every second for up to ConsistencyTimeout while it
+// returns errStale. fn may only return errStale when it is told to wait.
+func (c *Client) readThen(fn func(conn *ldap.Conn, wait bool) error) error {
+	until := time.Now().Add(c.ConsistencyTimeout)
+	for {
+		wait := time.Now().Before(until) && (c.deadline.IsZero() || time.Now().Before(c.deadline))
+		err := c.bindThen(func(conn *ldap.Conn) error {
+			return fn(conn, wait)
+		})
+		if err != errStale {
 			return err
 		}
-	} else {
-		if err := conn.UnauthenticatedBind(c.BindDN); err != nil {
-			return err
+		log.Printf("[DEBUG] Waiting for a write to replicate to %s", c.Server)
+		time.Sleep(consistencyPollInterval)
+	}
+}
+
+// recordWrite records that attributes were written to dn on conn by a pinned client.
+func (c *Client) recordWrite(conn *ldap.Conn, dn string, attributes Attributes) {
+	if c.session != nil {
+		c.session.written[dnKey(dn)] = sessionWrite{conn: conn, attributes: attributes}
+	}
+}
+
+// stale reports whether entry, read from dn on conn, lacks a value written to it earlier in the session
+// on a different connection, which may have been served by another replica. A nil entry was not found.
+// Values are compared ignoring case, distinguished names such as members in their parsed form, and attributes
+// that are not returned, such as passwords, are skipped.
+func (c *Client) stale(conn *ldap.Conn, dn string, entry *Attributes) bool {
+	if c.session == nil {
+		return false
+	}
+	write, ok := c.session.written[dnKey(dn)]
+	if !ok || write.conn == conn {
+		return false
+	}
+	if entry == nil {
+		return true
+	}
+	for key, values := range write.attributes.Map {
+		name, ok := entry.Lookup(key)
+		if !ok {
+			continue
 		}
+		read := entry.Get(name)
+		var dns map[string]bool
+		for _, value := range values {
+			if value == "" || containsFold(read, value) {
+				continue
+			}
+			if dns == nil { // Distinguished names such as members may be returned in another form
+				dns = make(map[string]bool)
+				for _, v := range read {
+					dns[dnKey(v)] = true
+				}
+			}
+			if !dns[dnKey(value)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// entryAttributes returns the attributes of the first of entries, or nil when there are none.
+func entryAttributes(entries []*ldap.Entry) *Attributes {
+	if len(entries) == 0 {
+		return nil
 	}
-	return fn(conn)
+	m := make(map[string][]string)
+	for _, attr := range entries[0].Attributes {
+		m[attr.Name] = attr.Values
+	}
+	return &Attributes{m}
+}
+
+// isRetryable reports whether err is a transient failure that may succeed if the request is repeated.
+func isRetryable(err error) bool {
+	return isResultCode(err, ldap.LDAPResultBusy, ldap.LDAPResultUnavailable, ldap.LDAPResultServerDown, ldap.LDAPResultTimeout,
+		ldap.LDAPResultConnectError, ldap.ErrorNetwork)
 }
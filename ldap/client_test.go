@@ -0,0 +1,140 @@
+package ldap
+
+import (
+	"errors"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/go-ldap/ldap/v3"
+	"strings"
+	"testing"
+)
+
+func TestClient_searchUnknownNamingValue(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "uid=alice,dc=example,dc=com"
+	server.Put(dn, map[string][]string{"objectClass": {"top", "person", "inetOrgPerson"}, "uid": {"alice"}, "cn": {"Alice"}, "sn": {"Smith"}})
+	client := testAccClient(t, server)
+	// The DN is known but the naming value is not, as when the entry is imported
+	u := &User{DN: dn, RDNAttribute: "uid", ObjectClass: []string{"person"}}
+	if attribute, value := u.GetNamingAttribute(); attribute != "uid" || value != "" {
+		t.Fatalf("GetNamingAttribute = %q, %q, expected \"uid\", \"\"", attribute, value)
+	}
+	if err := client.Search(u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Uid != "alice" || u.Surname != "Smith" {
+		t.Errorf("Search read uid %q and sn %q, expected \"alice\" and \"Smith\"", u.Uid, u.Surname)
+	}
+}
+
+func TestClient_searchMoved(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	client := testAccClient(t, server)
+	moved := "cn=Alice,ou=Staff,dc=example,dc=com"
+	server.Put(moved, map[string][]string{"objectClass": {"top", "person"}, "cn": {"Alice"}, "sn": {"Smith"}})
+	u := &User{DN: "cn=Alice,dc=example,dc=com", CommonName: "Alice", RDNAttribute: "cn", Path: "dc=example,dc=com", ObjectClass: []string{"person"}}
+	if err := client.Search(u); err != nil {
+		t.Fatal(err)
+	}
+	if u.DN != moved || u.Surname != "Smith" {
+		t.Errorf("Search read %q with sn %q, expected the entry moved to %q", u.DN, u.Surname, moved)
+	}
+	server.Put("cn=Alice,ou=Contractors,dc=example,dc=com", map[string][]string{"objectClass": {"top", "person"}, "cn": {"Alice"}, "sn": {"Jones"}})
+	u = &User{CommonName: "Alice", RDNAttribute: "cn", Path: "dc=example,dc=com", ObjectClass: []string{"person"}}
+	if err := client.Search(u); err == nil || !strings.Contains(err.Error(), "Non-unique") {
+		t.Errorf("Search returned %v, expected a non-unique result", err)
+	}
+	// The entry is read directly when its path does not exist, as for a naming context root
+	root := &DomainComponent{DomainComponent: "example", Path: "dc=com", ObjectClass: []string{"domain"}}
+	if err := client.Search(root); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_retry(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	client := testAccClient(t, server)
+	client.MaxRetries = 1
+	ou := &OrganizationalUnit{OrganizationalUnit: "People", Path: "dc=example,dc=com", ObjectClass: []string{"top", "organizationalUnit"}}
+
+	// A write is not repeated once it has been sent, since it may have been performed
+	server.Fail(ldaptest.Fault{Operation: ldaptest.OperationAdd, ResultCode: ldap.LDAPResultBusy, Count: 1})
+	if err := client.Add(ou); !isResultCode(err, ldap.LDAPResultBusy) {
+		t.Fatalf("Add returned %v, expected Busy", err)
+	}
+	// but is when binding fails before it is sent
+	server.Fail(ldaptest.Fault{Operation: ldaptest.OperationBind, ResultCode: ldap.LDAPResultBusy, Count: 1})
+	if err := client.Add(ou); err != nil {
+		t.Fatalf("Add returned %v after a failed bind, expected it to be retried", err)
+	}
+	// Reads are repeated
+	server.Fail(ldaptest.Fault{Operation: ldaptest.OperationSearch, ResultCode: ldap.LDAPResultUnavailable, Count: 1})
+	if entry, err := client.ReadEntry("ou=People,dc=example,dc=com", []string{"ou"}); err != nil || entry == nil {
+		t.Fatalf("ReadEntry returned %v, %v, expected it to be retried", entry, err)
+	}
+}
+
+func TestClient_stale(t *testing.T) {
+	dn := "cn=admins,dc=example,dc=com"
+	client := (&Client{}).Pin()
+	written, other := &ldap.Conn{}, &ldap.Conn{}
+	client.recordWrite(written, dn, Attributes{map[string][]string{
+		"description": {"Administrators"},
+		"member":      {"cn=Alice Smith,ou=People,dc=example,dc=com"},
+	}})
+	read := func(description string, member string) *Attributes {
+		return &Attributes{map[string][]string{"description": {description}, "member": {member}}}
+	}
+	// Active Directory returns distinguished names with its own case and spacing
+	if client.stale(other, dn, read("administrators", "CN=Alice Smith, OU=People, DC=example, DC=com")) {
+		t.Error("stale returned true for an entry holding the written values, expected false")
+	}
+	if !client.stale(other, dn, read("Administrators", "cn=Bob Jones,ou=People,dc=example,dc=com")) {
+		t.Error("stale returned false for an entry without the written member, expected true")
+	}
+	if !client.stale(other, dn, nil) {
+		t.Error("stale returned false for a missing entry, expected true")
+	}
+	if client.stale(written, dn, nil) {
+		t.Error("stale returned true for a read on the connection that wrote, expected false")
+	}
+}
+
+func TestClient_globalCatalog(t *testing.T) {
+	cases := []struct {
+		server        string
+		globalCatalog string
+		expected      string
+	}{
+		{"ldap://10.0.0.1", "", "ldap://10.0.0.1:3268"},
+		{"ldaps://10.0.0.1:636", "", "ldaps://10.0.0.1:3269"},
+		{"ldap://10.0.0.1:389", "ldap://gc.example.com:3268", "ldap://gc.example.com:3268"},
+	}
+	for _, c := range cases {
+		client := (&Client{Server: c.server, GlobalCatalog: c.globalCatalog}).Pin()
+		gc, err := client.globalCatalog()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gc.Server != c.expected || gc.session != nil {
+			t.Errorf("Global Catalog of %s = %s, expected unpinned %s", c.server, gc.Server, c.expected)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := map[error]bool{
+		ldap.NewError(ldap.LDAPResultBusy, errors.New("busy")):                            true,
+		ldap.NewError(ldap.LDAPResultUnavailable, errors.New("unavailable")):              true,
+		ldap.NewError(ldap.LDAPResultTimeout, errors.New("timeout")):                      true,
+		ldap.NewError(ldap.ErrorNetwork, errors.New("connection reset")):                  true,
+		&Error{Operation: "add", Err: ldap.NewError(ldap.LDAPResultBusy, errors.New(""))}: true,
+		ldap.NewError(ldap.LDAPResultEntryAlreadyExists, errors.New("exists")):            false,
+		ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("credentials")):       false,
+		errors.New("not an LDAP error"):                                                   false,
+	}
+	for err, expected := range cases {
+		if actual := isRetryable(err); actual != expected {
+			t.Errorf("isRetryable(%v) = %t, expected %t", err, actual, expected)
+		}
+	}
+}
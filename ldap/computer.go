@@ -0,0 +1,122 @@
+package ldap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	COMPUTER                  = "computer"
+	ACCOUNTDISABLE            = 0x00000002
+	PASSWD_NOTREQD            = 0x00000020
+	WORKSTATION_TRUST_ACCOUNT = 0x00001000
+	NETBIOS_NAME_MAX_LENGTH   = 15
+)
+
+type Computer struct {
+	CommonName             string
+	Description            string
+	DisplayName            string
+	DN                     string
+	DNSHostName            string
+	Enabled                bool
+	ExtraAttributes        Attributes
+	Location               string
+	ManagedBy              string
+	Name                   string
+	ObjectClass            []string
+	OperatingSystem        string
+	OperatingSystemVersion string
+	Path                   string
+	SamAccountName         string
+	ServicePrincipalNames  []string
+	UserAccountControl     int
+}
+
+func (c *Computer) GetAttributes() Attributes {
+	m := map[string][]string{
+		"cn":                     {c.CommonName},
+		"description":            {c.Description},
+		"displayName":            {c.DisplayName},
+		"dNSHostName":            {c.DNSHostName},
+		"location":               {c.Location},
+		"managedBy":              {c.ManagedBy},
+		"name":                   {c.Name},
+		"objectClass":            c.ObjectClass,
+		"operatingSystem":        {c.OperatingSystem},
+		"operatingSystemVersion": {c.OperatingSystemVersion},
+		"sAMAccountName":         {c.samAccountName()},
+		"servicePrincipalName":   c.ServicePrincipalNames,
+		"userAccountControl":     {""},
+	}
+	// Pre-staged accounts have no password until the computer joins the domain
+	userAccountControl := c.UserAccountControl
+	if userAccountControl == 0 {
+		userAccountControl = WORKSTATION_TRUST_ACCOUNT | PASSWD_NOTREQD
+	}
+	// Only the disabled flag is managed, keeping flags changed by the domain join or administrators
+	if c.Enabled {
+		userAccountControl &^= ACCOUNTDISABLE
+	} else {
+		userAccountControl |= ACCOUNTDISABLE
+	}
+	m["userAccountControl"] = []string{fmt.Sprintf("%d", userAccountControl)}
+	attributes := Attributes{m}
+	attributes.Merge(c.ExtraAttributes)
+	return attributes
+}
+
+func (c *Computer) SetAttributes(attributes Attributes) {
+	c.CommonName = attributes.GetFirst("cn")
+	c.Description = attributes.GetFirst("description")
+	c.DisplayName = attributes.GetFirst("displayName")
+	c.DNSHostName = attributes.GetFirst("dNSHostName")
+	c.ExtraAttributes = attributes.Select(c.ExtraAttributes.Keys())
+	c.Location = attributes.GetFirst("location")
+	c.ManagedBy = attributes.GetFirst("managedBy")
+	c.Name = attributes.GetFirst("name")
+	c.ObjectClass = attributes.Get("objectClass")
+	c.OperatingSystem = attributes.GetFirst("operatingSystem")
+	c.OperatingSystemVersion = attributes.GetFirst("operatingSystemVersion")
+	c.SamAccountName = attributes.GetFirst("sAMAccountName")
+	c.ServicePrincipalNames = attributes.Get("servicePrincipalName")
+	if attributes.HasValue("userAccountControl") {
+		userAccountControl, _ := strconv.Atoi(attributes.GetFirst("userAccountControl"))
+		c.Enabled = userAccountControl&ACCOUNTDISABLE == 0
+		c.UserAccountControl = userAccountControl
+	}
+}
+
+// samAccountName returns the SAM account name of the computer, which defaults to its NetBIOS name, the
+// upper-case common name, followed by '$'.
+func (c *Computer) samAccountName() string {
+	if c.SamAccountName == "" && c.CommonName != "" {
+		return strings.ToUpper(c.CommonName) + "$"
+	}
+	return c.SamAccountName
+}
+
+func (c *Computer) GetObjectClass() []string {
+	return c.ObjectClass
+}
+
+func (c *Computer) GetDN() string {
+	return c.DN
+}
+
+func (c *Computer) GetPath() string {
+	return c.Path
+}
+
+func (c *Computer) GetRelativeDN() string {
+	return "cn=" + c.CommonName
+}
+
+func (c *Computer) GetNamingAttribute() (string, string) {
+	return "cn", c.CommonName
+}
+
+func (c *Computer) SetDN(dn string) {
+	c.DN = dn
+}
@@ -0,0 +1,25 @@
+package ldap
+
+import (
+	"testing"
+)
+
+func TestComputer_userAccountControl(t *testing.T) {
+	cases := []struct {
+		userAccountControl int
+		enabled            bool
+		expected           string
+	}{
+		{0, true, "4128"},          // WORKSTATION_TRUST_ACCOUNT | PASSWD_NOTREQD
+		{0, false, "4130"},         // and ACCOUNTDISABLE
+		{0x81000, false, "528386"}, // TRUSTED_FOR_DELEGATION set after the join is kept
+		{0x81002, true, "528384"},
+	}
+	for _, c := range cases {
+		computer := &Computer{CommonName: "web01", Enabled: c.enabled, UserAccountControl: c.userAccountControl}
+		attributes := computer.GetAttributes()
+		if actual := attributes.GetFirst("userAccountControl"); actual != c.expected {
+			t.Errorf("userAccountControl of %#x with enabled %t = %s, expected %s", c.userAccountControl, c.enabled, actual, c.expected)
+		}
+	}
+}
@@ -1,16 +1,50 @@
 package ldap
 
+import "time"
+
 type Config struct {
-	Server       string
-	BindDN       string
-	BindPassword string
+	Server             string
+	BindDN             string
+	BindPassword       string
+	BaseDN             string
+	ConnectTimeout     time.Duration
+	ConsistencyTimeout time.Duration
+	Flavor             string
+	GlobalCatalog      string
+	LDIFOutputFile     string
+	MaxRetries         int
+	ReadOnly           bool
+	RequestTimeout     time.Duration
+	ValidateSchema     bool
 }
 
 func (c *Config) Client() (interface{}, error) {
 	client := &Client{
-		Server:       c.Server,
-		BindDN:       c.BindDN,
-		BindPassword: c.BindPassword,
+		Server:             c.Server,
+		BindDN:             c.BindDN,
+		BindPassword:       c.BindPassword,
+		BaseDN:             c.BaseDN,
+		ConnectTimeout:     c.ConnectTimeout,
+		ConsistencyTimeout: c.ConsistencyTimeout,
+		Flavor:             c.Flavor,
+		GlobalCatalog:      c.GlobalCatalog,
+		LDIFOutputFile:     c.LDIFOutputFile,
+		MaxRetries:         c.MaxRetries,
+		ReadOnly:           c.ReadOnly,
+		RequestTimeout:     c.RequestTimeout,
+		ValidateSchema:     c.ValidateSchema,
+		shared:             &clientShared{},
+	}
+	rootDSE, err := client.ReadRootDSE(rootDSEAttributes...)
+	if err != nil {
+		return nil, err
+	}
+	client.RootDSE = rootDSE
+	if client.Flavor == "" || client.Flavor == FLAVOR_AUTO {
+		client.Flavor = detectFlavor(rootDSE)
+	}
+	if client.BaseDN == "" {
+		client.BaseDN = detectBaseDN(rootDSE)
 	}
 	return client, nil
 }
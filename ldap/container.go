@@ -0,0 +1,58 @@
+package ldap
+
+const (
+	CONTAINER = "container"
+)
+
+type Container struct {
+	CommonName      string
+	Description     string
+	DN              string
+	ExtraAttributes Attributes
+	Name            string
+	ObjectClass     []string
+	Path            string
+}
+
+func (c *Container) GetAttributes() Attributes {
+	attributes := Attributes{map[string][]string{
+		"cn":          {c.CommonName},
+		"description": {c.Description},
+		"name":        {c.Name},
+		"objectClass": c.ObjectClass,
+	}}
+	attributes.Merge(c.ExtraAttributes)
+	return attributes
+}
+
+func (c *Container) SetAttributes(attributes Attributes) {
+	c.CommonName = attributes.GetFirst("cn")
+	c.Description = attributes.GetFirst("description")
+	c.ExtraAttributes = attributes.Select(c.ExtraAttributes.Keys())
+	c.Name = attributes.GetFirst("name")
+	c.ObjectClass = attributes.Get("objectClass")
+}
+
+func (c *Container) GetObjectClass() []string {
+	return c.ObjectClass
+}
+
+func (c *Container) GetDN() string {
+	return c.DN
+}
+
+func (c *Container) GetPath() string {
+	return c.Path
+}
+
+func (c *Container) GetRelativeDN() string {
+	return "cn=" + c.CommonName
+}
+
+func (c *Container) GetNamingAttribute() (string, string) {
+	return "cn", c.CommonName
+}
+
+func (c *Container) SetDN(dn string) {
+	c.DN = dn
+}
@@ -0,0 +1,62 @@
+package ldap
+
+const (
+	DC_OBJECT = "dcObject"
+)
+
+type DomainComponent struct {
+	Description     string
+	DN              string
+	DomainComponent string
+	ExtraAttributes Attributes
+	ObjectClass     []string
+	Organization    string
+	Path            string
+}
+
+func (dc *DomainComponent) GetAttributes() Attributes {
+	attributes := Attributes{map[string][]string{
+		"dc":          {dc.DomainComponent},
+		"description": {dc.Description},
+		"objectClass": dc.ObjectClass,
+		"o":           {dc.Organization},
+	}}
+	if dc.Organization == "" && containsFold(dc.ObjectClass, ORGANIZATION) {
+		// The organization class requires 'o'; default it to the domain component
+		attributes.Map["o"] = []string{dc.DomainComponent}
+	}
+	attributes.Merge(dc.ExtraAttributes)
+	return attributes
+}
+
+func (dc *DomainComponent) SetAttributes(attributes Attributes) {
+	dc.Description = attributes.GetFirst("description")
+	dc.DomainComponent = attributes.GetFirst("dc")
+	dc.ExtraAttributes = attributes.Select(dc.ExtraAttributes.Keys())
+	dc.ObjectClass = attributes.Get("objectClass")
+	dc.Organization = attributes.GetFirst("o")
+}
+
+func (dc *DomainComponent) GetObjectClass() []string {
+	return dc.ObjectClass
+}
+
+func (dc *DomainComponent) GetDN() string {
+	return dc.DN
+}
+
+func (dc *DomainComponent) GetPath() string {
+	return dc.Path
+}
+
+func (dc *DomainComponent) GetRelativeDN() string {
+	return "dc=" + dc.DomainComponent
+}
+
+func (dc *DomainComponent) GetNamingAttribute() (string, string) {
+	return "dc", dc.DomainComponent
+}
+
+func (dc *DomainComponent) SetDN(dn string) {
+	dc.DN = dn
+}
@@ -0,0 +1,81 @@
+package ldap
+
+import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"strings"
+)
+
+// Entry is a directory entry of arbitrary object classes, described only by its attributes.
+type Entry struct {
+	Attributes Attributes
+	DN         string
+}
+
+// NewEntry returns the entry described by an LDIF content or add record.
+func NewEntry(record internal.LDIFRecord) *Entry {
+	m := make(map[string][]string)
+	keys := make(map[string]string)
+	for _, attribute := range record.Attributes {
+		key, ok := keys[strings.ToLower(attribute.Name)]
+		if !ok {
+			key = attribute.Name
+			keys[strings.ToLower(attribute.Name)] = key
+		}
+		m[key] = append(m[key], attribute.Value)
+	}
+	return &Entry{Attributes: Attributes{m}, DN: record.DN}
+}
+
+func (e *Entry) GetAttributes() Attributes {
+	return e.Attributes
+}
+
+func (e *Entry) SetAttributes(attributes Attributes) {
+	e.Attributes = attributes.Select(e.Attributes.Keys())
+}
+
+func (e *Entry) GetObjectClass() []string {
+	if key, ok := e.Attributes.Lookup("objectClass"); ok {
+		return e.Attributes.Get(key)
+	}
+	return nil
+}
+
+func (e *Entry) GetDN() string {
+	return e.DN
+}
+
+func (e *Entry) GetPath() string {
+	_, path := splitDN(e.DN)
+	return path
+}
+
+func (e *Entry) GetRelativeDN() string {
+	rdn, _ := splitDN(e.DN)
+	return rdn
+}
+
+func (e *Entry) GetNamingAttribute() (string, string) {
+	rdn, _ := splitDN(e.DN)
+	if separator := strings.IndexByte(rdn, '='); separator > 0 {
+		return rdn[:separator], rdn[separator+1:]
+	}
+	return rdn, ""
+}
+
+func (e *Entry) SetDN(dn string) {
+	e.DN = dn
+}
+
+// splitDN splits dn at its first unescaped comma. The path of a single RDN is empty.
+func splitDN(dn string) (rdn string, path string) {
+	for i := 0; i < len(dn); i++ {
+		switch dn[i] {
+		case '\\':
+			i++
+		case ',':
+			return dn[:i], dn[i+1:]
+		}
+	}
+	return dn, ""
+}
@@ -0,0 +1,210 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-ldap/ldap/v3"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Error is a failed LDAP operation. It wraps the *ldap.Error returned by the server, so errors.As
+// and isResultCode see through it.
+type Error struct {
+	Operation  string
+	DN         string
+	Server     string
+	ResultCode uint16
+	MatchedDN  string
+	Diagnostic string
+	Details    []string
+	Err        error
+}
+
+// newError returns err as an *Error describing operation on dn, with details such as the search filter
+// or change list appended one per line. Errors that did not come from the LDAP library are returned as is.
+func (c *Client) newError(operation string, dn string, err error, details ...string) error {
+	var ldapErr *ldap.Error
+	if err == nil || !errors.As(err, &ldapErr) {
+		return err
+	}
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	diagnostic := ""
+	if ldapErr.Err != nil {
+		diagnostic = strings.TrimRight(ldapErr.Err.Error(), "\x00 \n")
+	}
+	return &Error{
+		Operation:  operation,
+		DN:         dn,
+		Server:     c.Server,
+		ResultCode: ldapErr.ResultCode,
+		MatchedDN:  ldapErr.MatchedDN,
+		Diagnostic: diagnostic,
+		Details:    details,
+		Err:        err,
+	}
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("LDAP " + e.Operation)
+	if e.DN != "" {
+		b.WriteString(fmt.Sprintf(" %q", e.DN))
+	}
+	b.WriteString(fmt.Sprintf(" failed: %s (%d)", e.ResultName(), e.ResultCode))
+	if e.Diagnostic != "" {
+		b.WriteString(": " + e.Diagnostic)
+	}
+	if explanation := explainDiagnostic(e.Diagnostic); explanation != "" {
+		b.WriteString("\n" + explanation)
+	}
+	if e.MatchedDN != "" {
+		b.WriteString("\nmatched DN: " + e.MatchedDN)
+	}
+	b.WriteString("\nserver: " + e.Server)
+	for _, detail := range e.Details {
+		b.WriteString("\n" + detail)
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ResultName returns the name of the result code, e.g. "Constraint Violation".
+func (e *Error) ResultName() string {
+	if name, ok := ldap.LDAPResultCodeMap[e.ResultCode]; ok {
+		return name
+	}
+	return "Unknown Result Code"
+}
+
+// isResultCode reports whether err is, or wraps, an LDAP error with one of codes.
+func isResultCode(err error, codes ...uint16) bool {
+	var ldapErr *ldap.Error
+	if !errors.As(err, &ldapErr) {
+		return false
+	}
+	for _, code := range codes {
+		if ldapErr.ResultCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// werrors describes common Windows error codes found at the start of Active Directory diagnostic
+// messages, e.g. "0000052D: Constraint violation - check password restrictions", and in the data
+// field of bind failures.
+var werrors = map[uint32]string{
+	0x00000005: "ERROR_ACCESS_DENIED: the bind account is not allowed to perform the operation",
+	0x0000001F: "ERROR_GEN_FAILURE: the server will not perform the operation, e.g. setting a password over an unencrypted connection",
+	0x00000056: "ERROR_INVALID_PASSWORD: the current password is incorrect",
+	0x00000057: "ERROR_INVALID_PARAMETER: an attribute value could not be converted to the attribute's syntax",
+	0x00000524: "ERROR_USER_EXISTS: the account name is already in use",
+	0x00000525: "ERROR_NO_SUCH_USER: the account does not exist",
+	0x0000052C: "ERROR_ILL_FORMED_PASSWORD: the password is not valid",
+	0x0000052D: "ERROR_PASSWORD_RESTRICTION: the password does not meet the length, complexity, age or history requirements of the domain password policy",
+	0x0000052E: "ERROR_LOGON_FAILURE: the bind DN or password is incorrect",
+	0x00000530: "ERROR_INVALID_LOGON_HOURS: the account is not allowed to log on at this time",
+	0x00000531: "ERROR_INVALID_WORKSTATION: the account is not allowed to log on from this computer",
+	0x00000532: "ERROR_PASSWORD_EXPIRED: the password of the bind account has expired",
+	0x00000533: "ERROR_ACCOUNT_DISABLED: the bind account is disabled",
+	0x00000701: "ERROR_ACCOUNT_EXPIRED: the bind account has expired",
+	0x00000773: "ERROR_PASSWORD_MUST_CHANGE: the password of the bind account must be changed before it can be used",
+	0x00000775: "ERROR_ACCOUNT_LOCKED_OUT: the bind account is locked out",
+	0x00002014: "ERROR_DS_OBJ_CLASS_VIOLATION: the entry does not satisfy the rules of its object classes",
+	0x00002015: "ERROR_DS_CANT_ON_NON_LEAF: the entry has children",
+	0x0000202F: "ERROR_DS_CONSTRAINT_VIOLATION: a value violates a constraint of the directory",
+	0x00002030: "ERROR_DS_NO_SUCH_OBJECT: the entry does not exist",
+	0x00002035: "ERROR_DS_UNWILLING_TO_PERFORM: the server is unwilling to perform the operation",
+	0x00002071: "ERROR_DS_OBJ_STRING_NAME_EXISTS: an entry with this name already exists",
+	0x00002082: "ERROR_DS_RANGE_CONSTRAINT: a value is outside the range the attribute allows",
+	0x00002083: "ERROR_DS_ATT_VAL_ALREADY_EXISTS: the attribute already holds the value",
+	0x0000208D: "ERROR_DS_OBJ_NOT_FOUND: the entry, or its parent, does not exist",
+	0x00002098: "ERROR_DS_INSUFF_ACCESS_RIGHTS: the bind account does not have permission to perform the operation",
+	0x000021C7: "ERROR_DS_SPN_VALUE_NOT_UNIQUE_IN_FOREST: the service principal name is already registered in the forest",
+	0x000021C8: "ERROR_DS_UPN_VALUE_NOT_UNIQUE_IN_FOREST: the user principal name is already in use in the forest",
+}
+
+var (
+	diagnosticWERROR = regexp.MustCompile(`^([0-9A-Fa-f]{8}): `)
+	diagnosticData   = regexp.MustCompile(`, data ([0-9A-Fa-f]+),`)
+)
+
+// explainDiagnostic describes the Windows error code of an Active Directory diagnostic message, preferring
+// the more specific code in the data field of bind failures, or returns "" if it has none that is known.
+func explainDiagnostic(diagnostic string) string {
+	if match := diagnosticData.FindStringSubmatch(diagnostic); match != nil {
+		if code, err := strconv.ParseUint(match[1], 16, 32); err == nil && werrors[uint32(code)] != "" {
+			return werrors[uint32(code)]
+		}
+	}
+	if match := diagnosticWERROR.FindStringSubmatch(diagnostic); match != nil {
+		if code, err := strconv.ParseUint(match[1], 16, 32); err == nil {
+			return werrors[uint32(code)]
+		}
+	}
+	return ""
+}
+
+// sensitiveAttributes are attributes whose values are never included in errors or logs.
+var sensitiveAttributes = []string{
+	"authPassword",
+	"dBCSPwd",
+	"krbPrincipalKey",
+	"lmPwdHistory",
+	"msDS-ManagedPassword",
+	"ntPwdHistory",
+	"sambaLMPassword",
+	"sambaNTPassword",
+	"supplementalCredentials",
+	"unicodePwd",
+	"userPassword",
+}
+
+// redact returns values, or a placeholder for each value if name is a sensitive attribute.
+func redact(name string, values []string) []string {
+	if !containsFold(sensitiveAttributes, name) {
+		return values
+	}
+	redacted := make([]string, len(values))
+	for i := range values {
+		redacted[i] = "(redacted)"
+	}
+	return redacted
+}
+
+// describeAttributes returns a line for each attribute, with the values of sensitive attributes redacted.
+func describeAttributes(attributes Attributes) []string {
+	keys := attributes.Keys()
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if attributes.HasValue(key) {
+			lines = append(lines, fmt.Sprintf("  %s: %s", key, strings.Join(redact(key, attributes.Get(key)), "; ")))
+		}
+	}
+	return lines
+}
+
+// describeChanges returns a line for each change of request, with the values of sensitive attributes redacted.
+func describeChanges(request *ldap.ModifyRequest) []string {
+	operations := map[uint]string{
+		ldap.AddAttribute:       "add",
+		ldap.DeleteAttribute:    "delete",
+		ldap.ReplaceAttribute:   "replace",
+		ldap.IncrementAttribute: "increment",
+	}
+	lines := make([]string, 0, len(request.Changes))
+	for _, change := range request.Changes {
+		name := change.Modification.Type
+		lines = append(lines, fmt.Sprintf("  %s %s: %s", operations[change.Operation], name, strings.Join(redact(name, change.Modification.Vals), "; ")))
+	}
+	return lines
+}
@@ -0,0 +1,85 @@
+package ldap
+
+import (
+	"errors"
+	"github.com/go-ldap/ldap/v3"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestError_Error(t *testing.T) {
+	client := &Client{Server: "ldap://10.0.0.1"}
+	cause := ldap.NewError(ldap.LDAPResultConstraintViolation, errors.New("0000052D: Constraint violation - check password restrictions\x00"))
+	err := client.newError("modify", "cn=alice,dc=example,dc=com", cause, "changes:", "  replace unicodePwd: (redacted)")
+	expected := strings.Join([]string{
+		`LDAP modify "cn=alice,dc=example,dc=com" failed: Constraint Violation (19): 0000052D: Constraint violation - check password restrictions`,
+		"ERROR_PASSWORD_RESTRICTION: the password does not meet the length, complexity, age or history requirements of the domain password policy",
+		"server: ldap://10.0.0.1",
+		"changes:",
+		"  replace unicodePwd: (redacted)",
+	}, "\n")
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, expected %q", err.Error(), expected)
+	}
+	if !isResultCode(err, ldap.LDAPResultConstraintViolation) {
+		t.Error("isResultCode did not see through the error")
+	}
+	if plain := errors.New("not an LDAP error"); client.newError("modify", "", plain) != plain {
+		t.Error("newError wrapped an error that did not come from the LDAP library")
+	}
+}
+
+func TestExplainDiagnostic(t *testing.T) {
+	cases := map[string]string{
+		"80090308: LdapErr: DSID-0C090447, comment: AcceptSecurityContext error, data 52e, v3839": werrors[0x52E],
+		"80090308: LdapErr: DSID-0C090447, comment: AcceptSecurityContext error, data 775, v3839": werrors[0x775],
+		"00002071: UpdErr: DSID-031B0D0E, problem 6005 (ENTRY_EXISTS), data 0":                    werrors[0x2071],
+		"0000207D: UpdErr: DSID-031B1A3F, problem 6003 (CANT_ADD_TO_OBJECT), data 0":              "",
+		"no global superior knowledge": "",
+		"":                             "",
+	}
+	for diagnostic, expected := range cases {
+		if actual := explainDiagnostic(diagnostic); actual != expected {
+			t.Errorf("explainDiagnostic(%q) = %q, expected %q", diagnostic, actual, expected)
+		}
+	}
+}
+
+func TestDescribeAttributes(t *testing.T) {
+	attributes := Attributes{map[string][]string{
+		"sn":           {"Smith"},
+		"objectClass":  {"top", "person"},
+		"description":  {""},
+		"userPassword": {"secret"},
+		"cn":           {"Alice Smith"},
+	}}
+	expected := []string{
+		"  cn: Alice Smith",
+		"  objectClass: top; person",
+		"  sn: Smith",
+		"  userPassword: (redacted)",
+	}
+	if actual := describeAttributes(attributes); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("describeAttributes returned %q, expected %q", actual, expected)
+	}
+}
+
+func TestDescribeChanges(t *testing.T) {
+	request := ldap.NewModifyRequest("cn=alice,dc=example,dc=com", nil)
+	request.Replace("mail", []string{"alice@example.com"})
+	request.Add("member", []string{"cn=a,dc=example,dc=com", "cn=b,dc=example,dc=com"})
+	request.Delete("description", nil)
+	request.Replace("UnicodePwd", []string{"\"secret\""})
+	request.Increment("uidNumber", "1")
+	expected := []string{
+		"  replace mail: alice@example.com",
+		"  add member: cn=a,dc=example,dc=com; cn=b,dc=example,dc=com",
+		"  delete description: ",
+		"  replace UnicodePwd: (redacted)",
+		"  increment uidNumber: 1",
+	}
+	if actual := describeChanges(request); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("describeChanges returned %q, expected %q", actual, expected)
+	}
+}
@@ -0,0 +1,90 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"sort"
+	"strings"
+)
+
+func extraAttributesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The LDAP display name of the attribute.",
+				},
+				"values": {
+					Type:        schema.TypeSet,
+					Required:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+					Set:         schema.HashString,
+					Description: "The values of the attribute.",
+				},
+			},
+		},
+		Description: "Specifies additional attributes not covered by the typed arguments. Only the configured attributes are tracked.",
+	}
+}
+
+// extraAttributesCustomizeDiff rejects extra attributes that duplicate one another or a typed argument of obj.
+func extraAttributesCustomizeDiff(obj Object) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, m interface{}) error {
+		set, ok := d.Get("attributes").(*schema.Set)
+		if !ok {
+			return nil
+		}
+		typed := obj.GetAttributes()
+		seen := make(map[string]bool)
+		for _, elem := range set.List() {
+			key := elem.(map[string]interface{})["name"].(string)
+			if seen[strings.ToLower(key)] {
+				return fmt.Errorf("attribute %q is configured more than once", key)
+			}
+			seen[strings.ToLower(key)] = true
+			if match, ok := typed.Lookup(key); ok {
+				return fmt.Errorf("attribute %q conflicts with a typed argument; configure %q through its argument instead", key, match)
+			}
+		}
+		return nil
+	}
+}
+
+func expandExtraAttributes(v interface{}) Attributes {
+	m := make(map[string][]string)
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return Attributes{m}
+	}
+	for _, elem := range set.List() {
+		attribute := elem.(map[string]interface{})
+		values := make([]string, 0)
+		for _, value := range attribute["values"].(*schema.Set).List() {
+			values = append(values, value.(string))
+		}
+		name := attribute["name"].(string)
+		m[name] = append(m[name], values...)
+	}
+	return Attributes{m}
+}
+
+func flattenExtraAttributes(attributes Attributes) []interface{} {
+	keys := attributes.Keys()
+	sort.Strings(keys)
+	list := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		values := make([]interface{}, 0)
+		for _, value := range attributes.Get(key) {
+			values = append(values, value)
+		}
+		list = append(list, map[string]interface{}{
+			"name":   key,
+			"values": schema.NewSet(schema.HashString, values),
+		})
+	}
+	return list
+}
@@ -0,0 +1,71 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"strings"
+)
+
+const (
+	FLAVOR_AUTO             = "auto"
+	FLAVOR_ACTIVE_DIRECTORY = "active_directory"
+	FLAVOR_OPENLDAP         = "openldap"
+	FLAVOR_389DS            = "389ds"
+
+	// capabilityActiveDirectory is the LDAP_CAP_ACTIVE_DIRECTORY_OID capability
+	capabilityActiveDirectory = "1.2.840.113556.1.4.800"
+)
+
+// rootDSEAttributes are the root DSE attributes read when the provider is configured.
+var rootDSEAttributes = []string{
+	"defaultNamingContext",
+	"forestFunctionality",
+	"namingContexts",
+	"objectClass",
+	"rootDomainNamingContext",
+	"subschemaSubentry",
+	"supportedCapabilities",
+	"supportedControl",
+	"supportedExtension",
+	"vendorName",
+	"vendorVersion",
+}
+
+// detectFlavor identifies the directory server implementation from its root DSE.
+func detectFlavor(rootDSE Attributes) string {
+	if rootDSE.HasValue("forestFunctionality") || containsFold(rootDSE.Get("supportedCapabilities"), capabilityActiveDirectory) {
+		return FLAVOR_ACTIVE_DIRECTORY
+	}
+	vendor := strings.ToLower(rootDSE.GetFirst("vendorName"))
+	for _, name := range []string{"389 project", "red hat", "fedora", "netscape"} {
+		if strings.Contains(vendor, name) {
+			return FLAVOR_389DS
+		}
+	}
+	return FLAVOR_OPENLDAP
+}
+
+// serverDefaultedAttributes returns the required attributes the server supplies when they are omitted.
+func serverDefaultedAttributes(flavor string) []string {
+	if flavor == FLAVOR_ACTIVE_DIRECTORY {
+		return []string{"groupType", "instanceType", "nTSecurityDescriptor", "objectCategory", "objectSid", "sAMAccountName"}
+	}
+	return []string{}
+}
+
+// activeDirectoryCustomizeDiff rejects arguments for attributes that only the Active Directory schema defines,
+// which are not written to servers of other flavors.
+func activeDirectoryCustomizeDiff(arguments ...string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, m interface{}) error {
+		client, ok := m.(*Client)
+		if !ok || client.Flavor == FLAVOR_ACTIVE_DIRECTORY {
+			return nil
+		}
+		for _, argument := range arguments {
+			if _, ok := d.GetOk(argument); ok {
+				return fmt.Errorf("%s is only supported by Active Directory, but the server flavor is %q", argument, client.Flavor)
+			}
+		}
+		return nil
+	}
+}
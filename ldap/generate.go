@@ -0,0 +1,258 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GenerateOptions selects the entries for which configuration is generated.
+type GenerateOptions struct {
+	Base  string
+	Types []string
+}
+
+// generator describes how entries of one resource type are recognized and marshalled.
+type generator struct {
+	resourceType  string
+	resource      *schema.Resource
+	rdnAttributes []string
+	objectClass   []string
+	marshal       func(dn string, rdn string, path string, attributes Attributes, d *schema.ResourceData) error
+}
+
+// generatedResource is a resource block generated from an entry.
+type generatedResource struct {
+	address string
+	dn      string
+	data    *schema.ResourceData
+	schema  map[string]*schema.Schema
+}
+
+// generatedReferenceKeys are arguments whose distinguished names are replaced with references to
+// other generated resources.
+var generatedReferenceKeys = map[string]bool{
+	"manager":        true,
+	"members":        true,
+	"path":           true,
+	"unique_members": true,
+}
+
+func generators() []generator {
+	return []generator{
+		{
+			resourceType:  "ldap_organizational_unit",
+			resource:      resourceLdapOrganizationalUnit(),
+			rdnAttributes: []string{"ou"},
+			objectClass:   []string{organizationalUnit},
+			marshal: func(dn string, rdn string, path string, attributes Attributes, d *schema.ResourceData) error {
+				ou := &OrganizationalUnit{DN: dn, Path: path}
+				ou.SetAttributes(attributes)
+				return resourceLdapOrganizationalUnitMarshal(ou, d)
+			},
+		},
+		{
+			resourceType:  "ldap_group",
+			resource:      resourceLdapGroup(),
+			rdnAttributes: rdnAttributes(groupRDNArguments),
+			objectClass:   []string{GROUP, GROUP_OF_NAMES, GROUP_OF_UNIQUE_NAMES, POSIX_GROUP},
+			marshal: func(dn string, rdn string, path string, attributes Attributes, d *schema.ResourceData) error {
+				g := &Group{DN: dn, Path: path}
+				if err := g.setRelativeDN(rdn); err != nil {
+					return err
+				}
+				g.SetAttributes(attributes)
+				return resourceLdapGroupMarshal(g, d)
+			},
+		},
+		{
+			resourceType:  "ldap_user",
+			resource:      resourceLdapUser(),
+			rdnAttributes: rdnAttributes(userRDNArguments),
+			objectClass:   []string{PERSON, INET_ORG_PERSON, POSIX_ACCOUNT, USER},
+			marshal: func(dn string, rdn string, path string, attributes Attributes, d *schema.ResourceData) error {
+				u := &User{DN: dn, Path: path}
+				if err := u.setRelativeDN(rdn); err != nil {
+					return err
+				}
+				u.SetAttributes(attributes)
+				return resourceLdapUserMarshal(u, d)
+			},
+		},
+	}
+}
+
+// Generate searches beneath options.Base and writes a resource block for every user, group and
+// organizational unit found, preceded by the command that imports it. Entries are mapped with the
+// same functions the resources use to read them, so the generated configuration plans clean.
+func Generate(client *Client, options GenerateOptions, w io.Writer) error {
+	base := options.Base
+	if base == "" {
+		base = client.BaseDN
+	}
+	if base == "" {
+		return fmt.Errorf("no search base was given and the server does not advertise a default naming context")
+	}
+	selected := make([]generator, 0)
+	filters := make([]string, 0)
+	for _, g := range generators() {
+		if len(options.Types) > 0 && !containsFold(options.Types, g.resourceType) {
+			continue
+		}
+		selected = append(selected, g)
+		for _, objectClass := range g.objectClass {
+			filters = append(filters, "(objectClass="+objectClass+")")
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no resource types selected; expected any of ldap_organizational_unit, ldap_group, ldap_user")
+	}
+	entries, err := client.SearchEntries(base, "(|"+strings.Join(filters, "")+")", []string{"*"})
+	if err != nil {
+		return err
+	}
+	dns := make([]string, 0, len(entries))
+	for dn := range entries {
+		dns = append(dns, dn)
+	}
+	sort.Slice(dns, func(i, j int) bool { // Parents before children, then alphabetically
+		di, dj := strings.Count(dns[i], ","), strings.Count(dns[j], ",")
+		if di != dj {
+			return di < dj
+		}
+		return strings.ToLower(dns[i]) < strings.ToLower(dns[j])
+	})
+	resources := make([]generatedResource, 0)
+	addresses := make(map[string]string)
+	names := make(map[string]bool)
+	for _, dn := range dns {
+		attributes := entries[dn]
+		g, ok := generatorFor(selected, attributes.Get("objectClass"))
+		if !ok {
+			continue
+		}
+		rdn, path := splitDN(dn)
+		_, value, err := parseRelativeDN(rdn, g.rdnAttributes)
+		if err != nil {
+			fmt.Fprintf(w, "# Skipped %s: %v\n\n", dn, err)
+			continue
+		}
+		d := g.resource.Data(nil)
+		if err := g.marshal(dn, rdn, path, attributes, d); err != nil {
+			return err
+		}
+		address := g.resourceType + "." + generatedName(value, g.resourceType, names)
+		addresses[dnKey(dn)] = address
+		resources = append(resources, generatedResource{address: address, dn: dn, data: d, schema: g.resource.Schema})
+	}
+	for _, r := range resources {
+		if err := r.write(w, addresses); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generatorFor returns the generator of the first resource type matching objectClass. Computer
+// accounts, which are also users in Active Directory, are not matched.
+func generatorFor(generators []generator, objectClass []string) (generator, bool) {
+	if containsFold(objectClass, COMPUTER) {
+		return generator{}, false
+	}
+	for _, g := range generators {
+		for _, class := range g.objectClass {
+			if containsFold(objectClass, class) {
+				return g, true
+			}
+		}
+	}
+	return generator{}, false
+}
+
+var generatedNameInvalid = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// generatedName returns a unique resource name derived from value.
+func generatedName(value string, resourceType string, names map[string]bool) string {
+	name := strings.Trim(generatedNameInvalid.ReplaceAllString(strings.ToLower(value), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	unique := name
+	for i := 2; names[resourceType+"."+unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	names[resourceType+"."+unique] = true
+	return unique
+}
+
+func (r generatedResource) write(w io.Writer, addresses map[string]string) error {
+	resourceType := r.address[:strings.IndexByte(r.address, '.')]
+	name := r.address[len(resourceType)+1:]
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("# terraform import %s %s\n", r.address, hclString(r.dn)))
+	b.WriteString(fmt.Sprintf("resource %q %q {\n", resourceType, name))
+	keys := make([]string, 0, len(r.schema))
+	width := 0
+	for key, s := range r.schema {
+		if !s.Optional && !s.Required {
+			continue
+		}
+		if s.Type != schema.TypeString && s.Type != schema.TypeInt && s.Type != schema.TypeSet && s.Type != schema.TypeList {
+			continue
+		}
+		if _, ok := r.data.GetOk(key); !ok || r.data.Get(key) == s.Default {
+			continue
+		}
+		if _, ok := s.Elem.(*schema.Resource); ok {
+			continue
+		}
+		keys = append(keys, key)
+		if len(key) > width {
+			width = len(key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := r.data.Get(key)
+		var text string
+		switch v := value.(type) {
+		case string:
+			text = hclValue(v, generatedReferenceKeys[key], addresses)
+		case int:
+			text = fmt.Sprintf("%d", v)
+		case *schema.Set:
+			text = hclList(v.List(), generatedReferenceKeys[key], addresses)
+		case []interface{}:
+			text = hclList(v, generatedReferenceKeys[key], addresses)
+		}
+		b.WriteString(fmt.Sprintf("  %-*s = %s\n", width, key, text))
+	}
+	b.WriteString("}\n\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func hclList(values []interface{}, reference bool, addresses map[string]string) string {
+	elems := make([]string, len(values))
+	for i, value := range values {
+		elems[i] = hclValue(value.(string), reference, addresses)
+	}
+	sort.Strings(elems)
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+// hclValue quotes value, or refers to the generated resource with that distinguished name.
+func hclValue(value string, reference bool, addresses map[string]string) string {
+	if address, ok := addresses[dnKey(value)]; reference && ok {
+		return address + ".id"
+	}
+	return hclString(value)
+}
+
+func hclString(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "${", "$${", "%{", "%%{")
+	return `"` + replacer.Replace(value) + `"`
+}
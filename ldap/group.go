@@ -3,6 +3,7 @@ package ldap
 import (
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -13,79 +14,80 @@ const (
 	UNIVERSAL                     = "Universal"
 	POSIX_GROUP                   = "posixGroup"
 	GROUP                         = "group"
+	GROUP_OF_NAMES                = "groupOfNames"
+	GROUP_OF_UNIQUE_NAMES         = "groupOfUniqueNames"
 	SAM_ALIAS_OBJECT              = "AliasObject"
 	SAM_GROUP_OBJECT              = "GroupObject"
 	SAM_NON_SECURITY_GROUP_OBJECT = "NonSecurityGroupObject"
 )
 
 type Group struct {
-	CommonName     string
-	Description    string
-	DN             string
-	DisplayName    string
-	GidNumber      int
-	GroupCategory  string
-	GroupScope     string
-	HomePage       string
-	Members        []string
-	MemberUids     []string
-	Name           string
-	ObjectClass    []string
-	Path           string
-	SamAccountName string
-	SamAccountType string
+	CommonName        string
+	Description       string
+	DN                string
+	DisplayName       string
+	ExtraAttributes   Attributes
+	Flavor            string
+	GidNumber         int
+	GroupCategory     string
+	GroupScope        string
+	HomePage          string
+	Members           []string
+	MemberUids        []string
+	Name              string
+	ObjectClass       []string
+	Path              string
+	PlaceholderMember string
+	RDNAttribute      string
+	SamAccountName    string
+	SamAccountType    string
+	UniqueMembers     []string
 }
 
 func (g *Group) GetAttributes() Attributes {
 	m := map[string][]string{
-		"description":    {g.Description},
-		"displayName":    {g.DisplayName},
-		"gidNumber":      {""},
-		"groupType":      {""},
-		"member":         g.Members,
-		"memberUid":      g.MemberUids,
-		"name":           {g.Name},
-		"objectClass":    g.ObjectClass,
-		"sAMAccountName": {g.SamAccountName},
-		"sAMAccountType": {""},
-		"wWWHomePage":    {g.HomePage},
+		"cn":           {g.CommonName},
+		"description":  {g.Description},
+		"displayName":  {g.DisplayName},
+		"gidNumber":    {""},
+		"member":       g.Members,
+		"memberUid":    g.MemberUids,
+		"name":         {g.Name},
+		"objectClass":  g.ObjectClass,
+		"uniqueMember": g.UniqueMembers,
+		"wWWHomePage":  {g.HomePage},
 	}
 	if g.GidNumber != 0 {
 		m["gidNumber"] = []string{strconv.Itoa(g.GidNumber)}
 	}
-	if g.GroupCategory != "" && g.GroupScope != "" {
-		// Group Category and Scope are stored as a single bitmask property 'groupType'
-		// https://docs.microsoft.com/en-us/windows/win32/adschema/a-grouptype
-		groupTypeMask := uint32(0)
-		if categoryMask, ok := g.groupCategoryMasks()[g.GroupCategory]; ok {
-			groupTypeMask |= categoryMask
-		}
-		if scopeMask, ok := g.groupScopeMasks()[g.GroupScope]; ok {
-			groupTypeMask |= scopeMask
-		}
-		m["groupType"] = []string{fmt.Sprintf("%d", groupTypeMask)}
+	// groupOfNames and groupOfUniqueNames require at least one member
+	if len(g.Members) == 0 && containsFold(g.ObjectClass, GROUP_OF_NAMES) {
+		m["member"] = []string{g.placeholderMember()}
 	}
-	if g.SamAccountType == SAM_GROUP_OBJECT {
-		m["sAMAccountType"] = []string{fmt.Sprintf("%d", 0x10000000)}
-	} else if g.SamAccountType == SAM_NON_SECURITY_GROUP_OBJECT {
-		m["sAMAccountType"] = []string{fmt.Sprintf("%d", 0x10000001)}
-	} else if g.SamAccountType == SAM_ALIAS_OBJECT {
-		m["sAMAccountType"] = []string{fmt.Sprintf("%d", 0x20000000)}
+	if len(g.UniqueMembers) == 0 && containsFold(g.ObjectClass, GROUP_OF_UNIQUE_NAMES) {
+		m["uniqueMember"] = []string{g.placeholderMember()}
+	}
+	if g.Flavor == FLAVOR_ACTIVE_DIRECTORY { // SAM and group type attributes only exist in Active Directory
+		g.setActiveDirectoryAttributes(m)
 	}
-	return Attributes{m}
+	attributes := Attributes{m}
+	attributes.Merge(g.ExtraAttributes)
+	return attributes
 }
 
 func (g *Group) SetAttributes(attributes Attributes) {
+	g.CommonName = attributes.GetFirst("cn")
 	g.Description = attributes.GetFirst("description")
 	g.DisplayName = attributes.GetFirst("displayName")
+	g.ExtraAttributes = attributes.Select(g.ExtraAttributes.Keys())
 	if attributes.HasValue("gidNumber") {
 		gidNumber, _ := strconv.Atoi(attributes.GetFirst("gidNumber"))
 		g.GidNumber = gidNumber
 	}
 	if attributes.HasValue("groupType") {
 		groupType := attributes.GetFirst("groupType")
-		mask, err := strconv.Atoi(groupType)
-		if err != nil {
+		mask, err := strconv.ParseInt(groupType, 10, 64)
+		if err == nil {
 			umask := uint32(mask)
 			categoryMasks := g.groupCategoryMasks()
 			if umask&categoryMasks[SECURITY] != 0 {
@@ -104,7 +106,7 @@ func (g *Group) SetAttributes(attributes Attributes) {
 		}
 	}
 	g.HomePage = attributes.GetFirst("wWWHomePage")
-	g.Members = attributes.Get("member")
+	g.Members = g.withoutPlaceholderMember(attributes.Get("member"))
 	g.MemberUids = attributes.Get("memberUid")
 	g.Name = attributes.GetFirst("name")
 	g.ObjectClass = attributes.Get("objectClass")
@@ -119,6 +121,7 @@ func (g *Group) SetAttributes(attributes Attributes) {
 			g.SamAccountType = SAM_ALIAS_OBJECT
 		}
 	}
+	g.UniqueMembers = g.withoutPlaceholderMember(attributes.Get("uniqueMember"))
 }
 
 func (g *Group) GetObjectClass() []string {
@@ -134,13 +137,84 @@ func (g *Group) GetPath() string {
 }
 
 func (g *Group) GetRelativeDN() string {
-	return "cn=" + g.CommonName
+	attribute, value := g.GetNamingAttribute()
+	return attribute + "=" + value
+}
+
+func (g *Group) GetNamingAttribute() (string, string) {
+	if g.RDNAttribute == "sAMAccountName" {
+		return "sAMAccountName", g.SamAccountName
+	}
+	return "cn", g.CommonName
+}
+
+// setRelativeDN sets the naming attribute of the group and its value from rdn, e.g. "cn=admins".
+func (g *Group) setRelativeDN(rdn string) error {
+	attribute, value, err := parseRelativeDN(rdn, rdnAttributes(groupRDNArguments))
+	if err != nil {
+		return err
+	}
+	g.RDNAttribute = attribute
+	if attribute == "sAMAccountName" {
+		g.SamAccountName = value
+	} else {
+		g.CommonName = value
+	}
+	return nil
 }
 
 func (g *Group) SetDN(dn string) {
 	g.DN = dn
 }
 
+func (g *Group) setActiveDirectoryAttributes(m map[string][]string) {
+	m["groupType"] = []string{""}
+	m["sAMAccountName"] = []string{g.SamAccountName}
+	m["sAMAccountType"] = []string{""}
+	if g.GroupCategory != "" && g.GroupScope != "" {
+		// Group Category and Scope are stored as a single bitmask property 'groupType'
+		// https://docs.microsoft.com/en-us/windows/win32/adschema/a-grouptype
+		groupTypeMask := uint32(0)
+		if categoryMask, ok := g.groupCategoryMasks()[g.GroupCategory]; ok {
+			groupTypeMask |= categoryMask
+		}
+		if scopeMask, ok := g.groupScopeMasks()[g.GroupScope]; ok {
+			groupTypeMask |= scopeMask
+		}
+		m["groupType"] = []string{fmt.Sprintf("%d", int32(groupTypeMask))} // groupType is a signed 32-bit integer
+	}
+	if g.SamAccountType == SAM_GROUP_OBJECT {
+		m["sAMAccountType"] = []string{fmt.Sprintf("%d", 0x10000000)}
+	} else if g.SamAccountType == SAM_NON_SECURITY_GROUP_OBJECT {
+		m["sAMAccountType"] = []string{fmt.Sprintf("%d", 0x10000001)}
+	} else if g.SamAccountType == SAM_ALIAS_OBJECT {
+		m["sAMAccountType"] = []string{fmt.Sprintf("%d", 0x20000000)}
+	}
+}
+
+// placeholderMember returns the member used to keep an otherwise empty groupOfNames valid,
+// which defaults to the group itself.
+func (g *Group) placeholderMember() string {
+	if g.PlaceholderMember != "" {
+		return g.PlaceholderMember
+	}
+	if g.DN != "" {
+		return g.DN
+	}
+	return fmt.Sprintf("%s,%s", g.GetRelativeDN(), g.GetPath())
+}
+
+func (g *Group) withoutPlaceholderMember(members []string) []string {
+	placeholder := g.placeholderMember()
+	filtered := make([]string, 0, len(members))
+	for _, member := range members {
+		if !strings.EqualFold(member, placeholder) {
+			filtered = append(filtered, member)
+		}
+	}
+	return filtered
+}
+
 func (g *Group) groupCategoryMasks() map[string]uint32 {
 	return map[string]uint32{
 		DISTRIBUTION: 0x00000000,
@@ -0,0 +1,76 @@
+package ldap
+
+import (
+	"testing"
+)
+
+func TestGroup_groupType(t *testing.T) {
+	cases := []struct {
+		category  string
+		scope     string
+		groupType string
+	}{
+		{SECURITY, GLOBAL, "-2147483646"},
+		{SECURITY, DOMAIN_LOCAL, "-2147483644"},
+		{SECURITY, UNIVERSAL, "-2147483640"},
+		{DISTRIBUTION, GLOBAL, "2"},
+		{DISTRIBUTION, DOMAIN_LOCAL, "4"},
+		{DISTRIBUTION, UNIVERSAL, "8"},
+	}
+	for _, c := range cases {
+		group := &Group{CommonName: "admins", GroupCategory: c.category, GroupScope: c.scope, Flavor: FLAVOR_ACTIVE_DIRECTORY, ObjectClass: []string{"top", GROUP}}
+		attributes := group.GetAttributes()
+		if actual := attributes.GetFirst("groupType"); actual != c.groupType {
+			t.Errorf("groupType of %s %s group = %q, expected %q", c.scope, c.category, actual, c.groupType)
+		}
+		read := &Group{}
+		read.SetAttributes(attributes)
+		if read.GroupCategory != c.category || read.GroupScope != c.scope {
+			t.Errorf("groupType %s = %s %s group, expected %s %s", c.groupType, read.GroupScope, read.GroupCategory, c.scope, c.category)
+		}
+	}
+}
+
+func TestGroup_groupTypeUnset(t *testing.T) {
+	group := &Group{CommonName: "admins", Flavor: FLAVOR_ACTIVE_DIRECTORY, ObjectClass: []string{"top", GROUP}}
+	if attributes := group.GetAttributes(); attributes.HasValue("groupType") {
+		t.Errorf("groupType = %q, expected no value without a category and scope", attributes.GetFirst("groupType"))
+	}
+	group = &Group{CommonName: "admins", GroupCategory: SECURITY, GroupScope: GLOBAL, ObjectClass: []string{"top", GROUP_OF_NAMES}}
+	if attributes := group.GetAttributes(); attributes.HasValue("groupType") {
+		t.Errorf("groupType = %q, expected no value outside Active Directory", attributes.GetFirst("groupType"))
+	}
+}
+
+func TestGroup_samAccountType(t *testing.T) {
+	cases := map[string]string{
+		SAM_GROUP_OBJECT:              "268435456",
+		SAM_NON_SECURITY_GROUP_OBJECT: "268435457",
+		SAM_ALIAS_OBJECT:              "536870912",
+	}
+	for samAccountType, value := range cases {
+		group := &Group{CommonName: "admins", SamAccountType: samAccountType, Flavor: FLAVOR_ACTIVE_DIRECTORY, ObjectClass: []string{"top", GROUP}}
+		attributes := group.GetAttributes()
+		if actual := attributes.GetFirst("sAMAccountType"); actual != value {
+			t.Errorf("sAMAccountType of %s = %q, expected %q", samAccountType, actual, value)
+		}
+		read := &Group{}
+		read.SetAttributes(attributes)
+		if read.SamAccountType != samAccountType {
+			t.Errorf("sAMAccountType %s = %q, expected %q", value, read.SamAccountType, samAccountType)
+		}
+	}
+}
+
+func TestGroup_placeholderMember(t *testing.T) {
+	group := &Group{CommonName: "admins", Path: "dc=example,dc=com", ObjectClass: []string{"top", GROUP_OF_NAMES}}
+	attributes := group.GetAttributes()
+	if actual := attributes.Get("member"); len(actual) != 1 || actual[0] != "cn=admins,dc=example,dc=com" {
+		t.Errorf("member of empty group = %q, expected the group itself", actual)
+	}
+	read := &Group{CommonName: "admins", Path: "dc=example,dc=com"}
+	read.SetAttributes(attributes)
+	if len(read.Members) != 0 {
+		t.Errorf("Members = %q, expected the placeholder to be removed", read.Members)
+	}
+}
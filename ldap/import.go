@@ -0,0 +1,78 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"sort"
+	"strings"
+)
+
+// importByIdentifier returns an importer that accepts either a distinguished name or one of
+// identifiers in the form "<attribute>=<value>", which is resolved to a distinguished name by
+// searching beneath the base DN for a single entry matching filter.
+func importByIdentifier(filter string, identifiers ...string) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		State: func(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			rdn, path := splitDN(d.Id())
+			separator := strings.IndexByte(rdn, '=')
+			if path != "" || separator < 1 || !containsFold(identifiers, rdn[:separator]) {
+				return []*schema.ResourceData{d}, nil
+			}
+			dn, err := resolveIdentifier(m.(*Client), filter, rdn[:separator], rdn[separator+1:])
+			if err != nil {
+				return nil, err
+			}
+			d.SetId(dn)
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+func resolveIdentifier(client *Client, filter string, attribute string, value string) (string, error) {
+	if client.BaseDN == "" {
+		return "", fmt.Errorf("cannot search for %s=%s: the provider has no base_dn and the server does not advertise a default naming context", attribute, value)
+	}
+	escaped := ldap.EscapeFilter(value)
+	if strings.EqualFold(attribute, "objectGUID") {
+		guid, err := objectGUIDFilterValue(value)
+		if err != nil {
+			return "", err
+		}
+		escaped = guid
+	}
+	search := fmt.Sprintf("(&%s(%s=%s))", filter, attribute, escaped)
+	entries, err := client.SearchEntries(client.BaseDN, search, []string{"1.1"})
+	if err != nil {
+		return "", err
+	}
+	dns := make([]string, 0, len(entries))
+	for dn := range entries {
+		dns = append(dns, dn)
+	}
+	sort.Strings(dns)
+	if len(dns) == 0 {
+		return "", fmt.Errorf("no entry matches %s=%s\nsearch base: %s\nfilter: %s", attribute, value, client.BaseDN, search)
+	} else if len(dns) > 1 {
+		return "", fmt.Errorf("%s=%s matches %d entries; import one by its distinguished name instead:\n  %s", attribute, value, len(dns), strings.Join(dns, "\n  "))
+	}
+	return dns[0], nil
+}
+
+// objectGUIDFilterValue converts the string form of an Active Directory objectGUID, e.g.
+// "{0b4d5a6e-1c2f-4a7b-9c3d-5e6f7a8b9c0d}", to its escaped binary form for use in a filter. The
+// first three groups are stored little-endian.
+func objectGUIDFilterValue(guid string) (string, error) {
+	s := strings.ReplaceAll(strings.Trim(guid, "{}"), "-", "")
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 16 || strings.Count(strings.Trim(guid, "{}"), "-") != 4 {
+		return "", fmt.Errorf("invalid objectGUID %q; expected the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", guid)
+	}
+	order := []int{3, 2, 1, 0, 5, 4, 7, 6, 8, 9, 10, 11, 12, 13, 14, 15}
+	var escaped strings.Builder
+	for _, i := range order {
+		escaped.WriteString(fmt.Sprintf("\\%02x", b[i]))
+	}
+	return escaped.String(), nil
+}
@@ -0,0 +1,227 @@
+package internal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// LDIFAttribute is a single attribute-value pair, in the order it appears in the document.
+type LDIFAttribute struct {
+	Name  string
+	Value string
+}
+
+// LDIFModification is one add, delete, replace or increment operation of a modify record.
+type LDIFModification struct {
+	Operation string
+	Name      string
+	Values    []string
+}
+
+// LDIFRecord is a content record, for which ChangeType is empty, or an add, modify, modrdn, moddn or delete
+// change record.
+type LDIFRecord struct {
+	DN            string
+	ChangeType    string
+	Attributes    []LDIFAttribute
+	Modifications []LDIFModification
+	NewRDN        string
+	DeleteOldRDN  bool
+	NewSuperior   string
+	Line          int
+}
+
+// ParseLDIF parses an RFC 2849 LDIF document into its records. Values given by URL and controls are not
+// supported.
+func ParseLDIF(document string) ([]LDIFRecord, error) {
+	records := make([]LDIFRecord, 0)
+	lines, numbers := unfoldLDIF(document)
+	for i := 0; i < len(lines); {
+		if lines[i] == "" {
+			i++
+			continue
+		}
+		start := i
+		for i < len(lines) && lines[i] != "" {
+			i++
+		}
+		if len(records) == 0 && strings.HasPrefix(strings.ToLower(lines[start]), "version:") {
+			if version := strings.TrimSpace(lines[start][len("version:"):]); version != "1" {
+				return nil, fmt.Errorf("line %d: unsupported LDIF version %q", numbers[start], version)
+			}
+			start++
+			if start == i {
+				continue
+			}
+		}
+		record, err := parseLDIFRecord(lines[start:i], numbers[start:i])
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// unfoldLDIF joins continuation lines, drops comments and returns each logical line with its line number.
+func unfoldLDIF(document string) ([]string, []int) {
+	lines := make([]string, 0)
+	numbers := make([]int, 0)
+	comment := false
+	for n, line := range strings.Split(strings.ReplaceAll(document, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, " ") {
+			if comment {
+				continue
+			}
+			if len(lines) > 0 && lines[len(lines)-1] != "" {
+				lines[len(lines)-1] += line[1:]
+				continue
+			}
+		}
+		comment = strings.HasPrefix(line, "#")
+		if comment {
+			continue
+		}
+		lines = append(lines, line)
+		numbers = append(numbers, n+1)
+	}
+	return lines, numbers
+}
+
+func parseLDIFRecord(lines []string, numbers []int) (record LDIFRecord, err error) {
+	record.Line = numbers[0]
+	name, value, err := parseLDIFLine(lines[0], numbers[0])
+	if err != nil {
+		return
+	}
+	if !strings.EqualFold(name, "dn") {
+		err = fmt.Errorf("line %d: expected dn, found %q", numbers[0], name)
+		return
+	}
+	record.DN = value
+	i := 1
+	if i < len(lines) {
+		if name, value, err = parseLDIFLine(lines[i], numbers[i]); err != nil {
+			return
+		}
+		switch {
+		case strings.EqualFold(name, "control"):
+			err = fmt.Errorf("line %d: controls are not supported", numbers[i])
+			return
+		case strings.EqualFold(name, "changetype"):
+			record.ChangeType = strings.ToLower(value)
+			i++
+		}
+	}
+	switch record.ChangeType {
+	case "", "add":
+		for ; i < len(lines); i++ {
+			if name, value, err = parseLDIFLine(lines[i], numbers[i]); err != nil {
+				return
+			}
+			record.Attributes = append(record.Attributes, LDIFAttribute{Name: name, Value: value})
+		}
+		if len(record.Attributes) == 0 {
+			err = fmt.Errorf("line %d: entry %q has no attributes", numbers[0], record.DN)
+		}
+	case "delete":
+		if i < len(lines) {
+			err = fmt.Errorf("line %d: unexpected content in delete record", numbers[i])
+		}
+	case "modrdn", "moddn":
+		for ; i < len(lines); i++ {
+			if name, value, err = parseLDIFLine(lines[i], numbers[i]); err != nil {
+				return
+			}
+			switch strings.ToLower(name) {
+			case "newrdn":
+				record.NewRDN = value
+			case "deleteoldrdn":
+				if value != "0" && value != "1" {
+					err = fmt.Errorf("line %d: deleteoldrdn must be 0 or 1, found %q", numbers[i], value)
+					return
+				}
+				record.DeleteOldRDN = value == "1"
+			case "newsuperior":
+				record.NewSuperior = value
+			default:
+				err = fmt.Errorf("line %d: unexpected %q in %s record", numbers[i], name, record.ChangeType)
+				return
+			}
+		}
+		if record.NewRDN == "" {
+			err = fmt.Errorf("line %d: %s record requires newrdn", numbers[0], record.ChangeType)
+		}
+	case "modify":
+		for i < len(lines) {
+			var modification LDIFModification
+			if modification.Operation, modification.Name, err = parseLDIFLine(lines[i], numbers[i]); err != nil {
+				return
+			}
+			modification.Operation = strings.ToLower(modification.Operation)
+			switch modification.Operation {
+			case "add", "delete", "replace", "increment":
+			default:
+				err = fmt.Errorf("line %d: unknown modify operation %q", numbers[i], modification.Operation)
+				return
+			}
+			operation := numbers[i]
+			for i++; i < len(lines) && lines[i] != "-"; i++ {
+				if name, value, err = parseLDIFLine(lines[i], numbers[i]); err != nil {
+					return
+				}
+				if !strings.EqualFold(name, modification.Name) {
+					err = fmt.Errorf("line %d: expected a value of %q, found %q", numbers[i], modification.Name, name)
+					return
+				}
+				modification.Values = append(modification.Values, value)
+			}
+			if i == len(lines) {
+				err = fmt.Errorf("line %d: modification of %q is not terminated by \"-\"", numbers[len(numbers)-1], modification.Name)
+				return
+			}
+			if modification.Operation == "increment" && len(modification.Values) != 1 {
+				err = fmt.Errorf("line %d: increment of %q requires exactly one value", operation, modification.Name)
+				return
+			}
+			i++
+			record.Modifications = append(record.Modifications, modification)
+		}
+		if len(record.Modifications) == 0 {
+			err = fmt.Errorf("line %d: modify record of %q has no modifications", numbers[0], record.DN)
+		}
+	default:
+		err = fmt.Errorf("line %d: unknown changetype %q", numbers[1], record.ChangeType)
+	}
+	return
+}
+
+// parseLDIFLine splits an attribute-value line, decoding base64 values.
+func parseLDIFLine(line string, number int) (name string, value string, err error) {
+	if line == "-" {
+		err = fmt.Errorf("line %d: unexpected \"-\"", number)
+		return
+	}
+	separator := strings.IndexByte(line, ':')
+	if separator < 1 {
+		err = fmt.Errorf("line %d: expected \"name: value\", found %q", number, line)
+		return
+	}
+	name = line[:separator]
+	value = line[separator+1:]
+	switch {
+	case strings.HasPrefix(value, ":"):
+		var decoded []byte
+		if decoded, err = base64.StdEncoding.DecodeString(strings.TrimLeft(value[1:], " ")); err != nil {
+			err = fmt.Errorf("line %d: invalid base64 value of %q: %v", number, name, err)
+			return
+		}
+		value = string(decoded)
+	case strings.HasPrefix(value, "<"):
+		err = fmt.Errorf("line %d: values given by URL are not supported", number)
+	default:
+		value = strings.TrimLeft(value, " ")
+	}
+	return
+}
@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLDIF(t *testing.T) {
+	document := strings.Join([]string{
+		"version: 1",
+		"# The organizational unit",
+		"#  continued comment",
+		"dn: ou=People,dc=example,dc=com",
+		"objectClass: organizationalUnit",
+		"description: Staff acc",
+		" ounts",
+		"",
+		"",
+		"dn:: dWlkPWFsaWNlLG91PVBlb3BsZSxkYz1leGFtcGxlLGRjPWNvbQ==",
+		"changetype: add",
+		"objectClass: inetOrgPerson",
+		"cn:: QWxpY2Ugw4RuZ3N0csO2bQ==",
+		"sn:  Smith",
+		"",
+		"dn: cn=admins,ou=Groups,dc=example,dc=com",
+		"changetype: modify",
+		"add: member",
+		"member: uid=alice,ou=People,dc=example,dc=com",
+		"-",
+		"delete: description",
+		"-",
+		"increment: uidNumber",
+		"uidNumber: 1",
+		"-",
+		"",
+		"dn: uid=bob,ou=People,dc=example,dc=com",
+		"changetype: modrdn",
+		"newrdn: uid=robert",
+		"deleteoldrdn: 1",
+		"newsuperior: ou=Staff,dc=example,dc=com",
+		"",
+		"dn: uid=carol,ou=People,dc=example,dc=com",
+		"changetype: delete",
+	}, "\r\n")
+	records, err := ParseLDIF(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []LDIFRecord{
+		{
+			DN: "ou=People,dc=example,dc=com",
+			Attributes: []LDIFAttribute{
+				{"objectClass", "organizationalUnit"},
+				{"description", "Staff accounts"},
+			},
+			Line: 4,
+		},
+		{
+			DN:         "uid=alice,ou=People,dc=example,dc=com",
+			ChangeType: "add",
+			Attributes: []LDIFAttribute{
+				{"objectClass", "inetOrgPerson"},
+				{"cn", "Alice Ängström"},
+				{"sn", "Smith"},
+			},
+			Line: 10,
+		},
+		{
+			DN:         "cn=admins,ou=Groups,dc=example,dc=com",
+			ChangeType: "modify",
+			Modifications: []LDIFModification{
+				{"add", "member", []string{"uid=alice,ou=People,dc=example,dc=com"}},
+				{"delete", "description", nil},
+				{"increment", "uidNumber", []string{"1"}},
+			},
+			Line: 16,
+		},
+		{
+			DN:           "uid=bob,ou=People,dc=example,dc=com",
+			ChangeType:   "modrdn",
+			NewRDN:       "uid=robert",
+			DeleteOldRDN: true,
+			NewSuperior:  "ou=Staff,dc=example,dc=com",
+			Line:         27,
+		},
+		{
+			DN:         "uid=carol,ou=People,dc=example,dc=com",
+			ChangeType: "delete",
+			Line:       33,
+		},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("ParseLDIF returned %+v, expected %+v", records, expected)
+	}
+}
+
+func TestParseLDIF_errors(t *testing.T) {
+	cases := []struct {
+		document string
+		expected string
+	}{
+		{"version: 2\n\ndn: ou=People,dc=example,dc=com\nou: People", `line 1: unsupported LDIF version "2"`},
+		{"ou: People", `line 1: expected dn, found "ou"`},
+		{"dn: ou=People,dc=example,dc=com", `line 1: entry "ou=People,dc=example,dc=com" has no attributes`},
+		{"dn: ou=People,dc=example,dc=com\nchangetype: rename", `line 2: unknown changetype "rename"`},
+		{"dn: ou=People,dc=example,dc=com\nchangetype: delete\nou: People", `line 3: unexpected content in delete record`},
+		{"dn: ou=People,dc=example,dc=com\nchangetype: modrdn\ndeleteoldrdn: 1", `line 1: modrdn record requires newrdn`},
+		{"dn: ou=People,dc=example,dc=com\nchangetype: moddn\nnewrdn: ou=Staff\ndeleteoldrdn: yes", `line 4: deleteoldrdn must be 0 or 1`},
+		{"dn: ou=People,dc=example,dc=com\nchangetype: modify", `line 1: modify record of "ou=People,dc=example,dc=com" has no modifications`},
+		{"dn: ou=People,dc=example,dc=com\nchangetype: modify\nreplace: description\ndescription: Staff", `line 4: modification of "description" is not terminated by "-"`},
+		{"dn: ou=People,dc=example,dc=com\nchangetype: modify\nreplace: description\nou: Staff\n-", `line 4: expected a value of "description", found "ou"`},
+		{"dn: ou=People,dc=example,dc=com\nchangetype: modify\nrename: description\n-", `line 3: unknown modify operation "rename"`},
+		{"dn: ou=People,dc=example,dc=com\nchangetype: modify\nincrement: uidNumber\n-", `line 3: increment of "uidNumber" requires exactly one value`},
+		{"dn: ou=People,dc=example,dc=com\ncontrol: 1.2.840.113556.1.4.805 true\nchangetype: delete", `line 2: controls are not supported`},
+		{"dn: ou=People,dc=example,dc=com\nou People", `line 2: expected "name: value", found "ou People"`},
+		{"dn: ou=People,dc=example,dc=com\ndescription:: not base64!", `line 2: invalid base64 value of "description"`},
+		{"dn: ou=People,dc=example,dc=com\njpegPhoto:< file:///tmp/photo.jpg", `line 2: values given by URL are not supported`},
+	}
+	for _, c := range cases {
+		if _, err := ParseLDIF(c.document); err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("ParseLDIF(%q) returned %v, expected %q", c.document, err, c.expected)
+		}
+	}
+}
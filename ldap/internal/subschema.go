@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// schemaFlags are the RFC 4512 description keywords that take no value.
+var schemaFlags = map[string]bool{
+	"ABSTRACT":             true,
+	"AUXILIARY":            true,
+	"COLLECTIVE":           true,
+	"NO-USER-MODIFICATION": true,
+	"OBSOLETE":             true,
+	"SINGLE-VALUE":         true,
+	"STRUCTURAL":           true,
+}
+
+// ParseSchemaDescription parses an RFC 4512 schema description such as an objectClasses or
+// attributeTypes value into its numeric OID and a map of keywords to values. Keywords without
+// a value map to an empty slice.
+func ParseSchemaDescription(description string) (oid string, fields map[string][]string, err error) {
+	tokens, err := tokenizeSchemaDescription(description)
+	if err != nil {
+		return
+	}
+	if len(tokens) < 3 || tokens[0] != "(" || tokens[len(tokens)-1] != ")" {
+		err = fmt.Errorf("invalid schema description: %s", description)
+		return
+	}
+	tokens = tokens[1 : len(tokens)-1]
+	oid = tokens[0]
+	fields = make(map[string][]string)
+	for i := 1; i < len(tokens); i++ {
+		keyword := strings.ToUpper(tokens[i])
+		if schemaFlags[keyword] {
+			fields[keyword] = []string{}
+			continue
+		}
+		if i+1 >= len(tokens) {
+			err = fmt.Errorf("missing value for %s in schema description: %s", keyword, description)
+			return
+		}
+		i++
+		if tokens[i] != "(" {
+			fields[keyword] = []string{tokens[i]}
+			continue
+		}
+		values := make([]string, 0)
+		for i++; i < len(tokens) && tokens[i] != ")"; i++ {
+			if tokens[i] != "$" {
+				values = append(values, tokens[i])
+			}
+		}
+		if i >= len(tokens) {
+			err = fmt.Errorf("unbalanced parentheses in schema description: %s", description)
+			return
+		}
+		fields[keyword] = values
+	}
+	return
+}
+
+func tokenizeSchemaDescription(description string) ([]string, error) {
+	tokens := make([]string, 0)
+	for i := 0; i < len(description); {
+		switch ch := description[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			i++
+		case ch == '(' || ch == ')' || ch == '$':
+			tokens = append(tokens, string(ch))
+			i++
+		case ch == '\'':
+			end := strings.IndexByte(description[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated quoted string in schema description: " + description)
+			}
+			tokens = append(tokens, description[i+1:i+1+end])
+			i += end + 2
+		default:
+			start := i
+			for i < len(description) && !strings.ContainsRune(" \t\n()$'", rune(description[i])) {
+				i++
+			}
+			tokens = append(tokens, description[start:i])
+		}
+	}
+	return tokens, nil
+}
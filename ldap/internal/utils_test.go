@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"testing"
+)
+
+func TestParseDN(t *testing.T) {
+	cases := []struct {
+		dn   string
+		rdn  string
+		path string
+	}{
+		{"cn=admins,dc=example,dc=com", "cn=admins", "dc=example,dc=com"},
+		{"ou=Sales,dc=com", "ou=Sales", "dc=com"},
+	}
+	for _, c := range cases {
+		rdn, path, err := ParseDN(c.dn)
+		if err != nil {
+			t.Errorf("ParseDN(%q) failed: %s", c.dn, err)
+		} else if rdn != c.rdn || path != c.path {
+			t.Errorf("ParseDN(%q) = %q, %q, expected %q, %q", c.dn, rdn, path, c.rdn, c.path)
+		}
+	}
+	for _, dn := range []string{"", "dc=com", ",dc=com", "cn=admins,"} {
+		if _, _, err := ParseDN(dn); err == nil {
+			t.Errorf("ParseDN(%q) succeeded, expected an error", dn)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		relativeDN  string
+		objectClass []string
+		filter      string
+	}{
+		{"cn=admins", []string{"top", "groupOfNames"}, "(&(cn=admins)(objectClass=top)(objectClass=groupOfNames))"},
+		{"ou=Sales", []string{"organizationalUnit"}, "(&(ou=Sales)(objectClass=organizationalUnit))"},
+		{"cn=admins", nil, "(&(cn=admins)(objectClass=*))"},
+		{"cn=admins", []string{}, "(&(cn=admins)(objectClass=*))"},
+	}
+	for _, c := range cases {
+		if actual := Filter(c.relativeDN, c.objectClass); actual != c.filter {
+			t.Errorf("Filter(%q, %q) = %q, expected %q", c.relativeDN, c.objectClass, actual, c.filter)
+		}
+	}
+}
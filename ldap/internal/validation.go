@@ -2,12 +2,15 @@ package internal
 
 import (
 	"fmt"
+	"github.com/go-ldap/ldap/v3"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"golang.org/x/crypto/ssh"
+	"time"
 )
 
 func SetIntersection(values []interface{}, minLen int) schema.SchemaValidateFunc {
 	return func(i interface{}, k string) (warnings []string, errors []error) {
-		s, ok := i.(schema.Set)
+		s, ok := i.(*schema.Set)
 		if !ok {
 			errors = append(errors, fmt.Errorf("expected type of %s to be schema.Set", k))
 			return warnings, errors
@@ -29,4 +32,58 @@ func SetIntersection(values []interface{}, minLen int) schema.SchemaValidateFunc
 		}
 		return warnings, errors
 	}
-}
\ No newline at end of file
+}
+
+// SSHPublicKey validates that a string is a single public key in OpenSSH authorized_keys format.
+func SSHPublicKey() schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (warnings []string, errors []error) {
+		v, ok := i.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+			return warnings, errors
+		}
+		if _, _, _, rest, err := ssh.ParseAuthorizedKey([]byte(v)); err != nil {
+			errors = append(errors, fmt.Errorf("expected %s to be an OpenSSH public key: %v", k, err))
+		} else if len(rest) > 0 {
+			errors = append(errors, fmt.Errorf("expected %s to contain a single OpenSSH public key", k))
+		}
+		return warnings, errors
+	}
+}
+
+// DistinguishedName validates that a string is a well-formed distinguished name.
+func DistinguishedName() schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (warnings []string, errors []error) {
+		v, ok := i.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+			return warnings, errors
+		}
+		if v == "" {
+			return warnings, errors
+		}
+		if dn, err := ldap.ParseDN(v); err != nil {
+			errors = append(errors, fmt.Errorf("expected %s to be a distinguished name: %v", k, err))
+		} else if len(dn.RDNs) == 0 {
+			errors = append(errors, fmt.Errorf("expected %s to be a non-empty distinguished name", k))
+		}
+		return warnings, errors
+	}
+}
+
+// Duration validates that a string is a duration such as "30s" or "5m" that is not negative.
+func Duration() schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (warnings []string, errors []error) {
+		v, ok := i.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+			return warnings, errors
+		}
+		if d, err := time.ParseDuration(v); err != nil {
+			errors = append(errors, fmt.Errorf("expected %s to be a duration such as \"30s\": %v", k, err))
+		} else if d < 0 {
+			errors = append(errors, fmt.Errorf("expected %s to not be negative", k))
+		}
+		return warnings, errors
+	}
+}
@@ -0,0 +1,146 @@
+package ldaptest
+
+import (
+	"fmt"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"strconv"
+	"strings"
+)
+
+// match evaluates an encoded search filter against e.
+func match(filter *ber.Packet, e *entry) (bool, error) {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if ok, err := match(child, e); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if ok, err := match(child, e); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case ldap.FilterNot:
+		ok, err := match(filter.Children[0], e)
+		return !ok, err
+	case ldap.FilterPresent:
+		_, ok := lookup(e.attributes, filter.Data.String())
+		return ok, nil
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch, ldap.FilterGreaterOrEqual, ldap.FilterLessOrEqual:
+		values := attributeValues(e, filter.Children[0].Data.String())
+		assertion := filter.Children[1].Data.String()
+		for _, value := range values {
+			if compare(filter.Tag, value, assertion) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case ldap.FilterSubstrings:
+		values := attributeValues(e, filter.Children[0].Data.String())
+		for _, value := range values {
+			if substrings(strings.ToLower(value), filter.Children[1].Children) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case ldap.FilterExtensibleMatch:
+		var rule, name, assertion string
+		for _, child := range filter.Children {
+			switch child.Tag {
+			case ldap.MatchingRuleAssertionMatchingRule:
+				rule = child.Data.String()
+			case ldap.MatchingRuleAssertionType:
+				name = child.Data.String()
+			case ldap.MatchingRuleAssertionMatchValue:
+				assertion = child.Data.String()
+			}
+		}
+		for _, value := range attributeValues(e, name) {
+			if extensibleMatch(rule, value, assertion) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, ldap.NewError(ldap.LDAPResultProtocolError, fmt.Errorf("unsupported filter type %d", filter.Tag))
+}
+
+func compare(tag ber.Tag, value string, assertion string) bool {
+	switch tag {
+	case ldap.FilterGreaterOrEqual, ldap.FilterLessOrEqual:
+		cmp := strings.Compare(strings.ToLower(value), strings.ToLower(assertion))
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			if a, err := strconv.ParseInt(assertion, 10, 64); err == nil {
+				cmp = 0
+				if v < a {
+					cmp = -1
+				} else if v > a {
+					cmp = 1
+				}
+			}
+		}
+		if tag == ldap.FilterGreaterOrEqual {
+			return cmp >= 0
+		}
+		return cmp <= 0
+	}
+	return strings.EqualFold(value, assertion)
+}
+
+func substrings(value string, substrings []*ber.Packet) bool {
+	for i, substring := range substrings {
+		s := strings.ToLower(substring.Data.String())
+		switch substring.Tag {
+		case ldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(value, s) {
+				return false
+			}
+			value = value[len(s):]
+		case ldap.FilterSubstringsAny:
+			index := strings.Index(value, s)
+			if index < 0 {
+				return false
+			}
+			value = value[index+len(s):]
+		case ldap.FilterSubstringsFinal:
+			if i != len(substrings)-1 || !strings.HasSuffix(value, s) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// extensibleMatch supports the Active Directory bitwise AND and OR matching rules, and otherwise
+// compares values for equality.
+func extensibleMatch(rule string, value string, assertion string) bool {
+	switch rule {
+	case "1.2.840.113556.1.4.803", "1.2.840.113556.1.4.804":
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		a, err := strconv.ParseInt(assertion, 10, 64)
+		if err != nil {
+			return false
+		}
+		if rule == "1.2.840.113556.1.4.803" {
+			return v&a == a
+		}
+		return v&a != 0
+	}
+	return strings.EqualFold(value, assertion)
+}
+
+// attributeValues returns the values of the attribute name of e.
+func attributeValues(e *entry, name string) []string {
+	if k, ok := lookup(e.attributes, name); ok {
+		return e.attributes[k]
+	}
+	return nil
+}
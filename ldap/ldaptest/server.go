@@ -0,0 +1,809 @@
+// Package ldaptest provides an in-memory LDAP server for testing the provider, and modules that use it,
+// without a directory server. It implements simple bind, search, add, modify, modify DN and delete,
+// compares values ignoring case, and can fail requests with chosen result codes and behave like Active
+// Directory, limiting page sizes and returning large attributes by range. A subtree search of the empty
+// base covers every entry, as it does on an Active Directory Global Catalog.
+package ldaptest
+
+import (
+	"errors"
+	"fmt"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	OperationBind     = "bind"
+	OperationSearch   = "search"
+	OperationAdd      = "add"
+	OperationModify   = "modify"
+	OperationModifyDN = "modifyDN"
+	OperationDelete   = "delete"
+
+	DefaultSuffix       = "dc=example,dc=com"
+	DefaultBindDN       = "cn=admin,dc=example,dc=com"
+	DefaultBindPassword = "secret"
+
+	capabilityActiveDirectory = "1.2.840.113556.1.4.800"
+	controlTypeTreeDelete     = "1.2.840.113556.1.4.805"
+)
+
+// Config configures a Server. Empty fields take the Default values.
+type Config struct {
+	// Suffix is the naming context of the server, whose entry is created when the server starts. Like the
+	// other entries, it may be deleted and added again, which does not require its parent to exist.
+	Suffix string
+	// BindDN and BindPassword are the only credentials accepted by simple bind, besides anonymous bind.
+	BindDN       string
+	BindPassword string
+	// ActiveDirectory makes the root DSE advertise the Active Directory capability and the tree delete
+	// control, which the server then honors.
+	ActiveDirectory bool
+	// MaxPageSize limits the entries returned by a search, as MaxPageSize (1000) does in Active Directory.
+	// Searches without the paged results control fail with Size Limit Exceeded beyond it. 0 is unlimited.
+	MaxPageSize int
+	// MaxValRange limits the values returned for an attribute, as MaxValRange (1500) does in Active
+	// Directory. Larger attributes are returned by range, e.g. "member;range=0-1499". 0 is unlimited.
+	MaxValRange int
+}
+
+// Fault makes the requests it matches fail with ResultCode instead of being performed.
+type Fault struct {
+	// Operation is the operation to fail, one of the Operation constants, or "" for any.
+	Operation string
+	// DN is the DN of the request to fail, e.g. the bind DN or search base, or "" for any.
+	DN         string
+	ResultCode uint16
+	Message    string
+	// Count is the number of requests to fail, or 0 to fail every matching request.
+	Count int
+}
+
+// Server is a running in-memory LDAP server.
+type Server struct {
+	URL    string
+	Config Config
+
+	conns    map[net.Conn]struct{}
+	entries  map[string]*entry
+	faults   []*Fault
+	listener net.Listener
+	mutex    sync.Mutex
+}
+
+type entry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+// NewServer starts a server on a local port, holding the entry of the suffix of config.
+func NewServer(config Config) (*Server, error) {
+	if config.Suffix == "" {
+		config.Suffix = DefaultSuffix
+	}
+	if config.BindDN == "" {
+		config.BindDN = DefaultBindDN
+	}
+	if config.BindPassword == "" {
+		config.BindPassword = DefaultBindPassword
+	}
+	suffix, err := ldap.ParseDN(config.Suffix)
+	if err != nil || len(suffix.RDNs) == 0 {
+		return nil, fmt.Errorf("invalid suffix %q", config.Suffix)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		URL:      "ldap://" + listener.Addr().String(),
+		Config:   config,
+		conns:    make(map[net.Conn]struct{}),
+		entries:  make(map[string]*entry),
+		listener: listener,
+	}
+	attributes := map[string][]string{"objectClass": {"top"}}
+	for _, attribute := range suffix.RDNs[0].Attributes {
+		attributes[attribute.Type] = []string{attribute.Value}
+	}
+	if _, ok := lookup(attributes, "dc"); ok {
+		attributes["objectClass"] = append(attributes["objectClass"], "domain")
+	}
+	s.Put(config.Suffix, attributes)
+	go s.serve()
+	return s, nil
+}
+
+// Close stops the server and closes its connections.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	return err
+}
+
+// Get returns a copy of the attributes of the entry at dn, or nil if it does not exist.
+func (s *Server) Get(dn string) map[string][]string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	e, ok := s.entries[key(dn)]
+	if !ok {
+		return nil
+	}
+	attributes := make(map[string][]string, len(e.attributes))
+	for name, values := range e.attributes {
+		attributes[name] = append([]string{}, values...)
+	}
+	return attributes
+}
+
+// Put adds or replaces the entry at dn, without checking that its parent exists.
+func (s *Server) Put(dn string, attributes map[string][]string) {
+	copied := make(map[string][]string, len(attributes))
+	for name, values := range attributes {
+		copied[name] = append([]string{}, values...)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.entries[key(dn)] = &entry{dn: dn, attributes: copied}
+}
+
+// Delete removes the entry at dn and its descendants, and reports whether it existed.
+func (s *Server) Delete(dn string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	k := key(dn)
+	if _, ok := s.entries[k]; !ok {
+		return false
+	}
+	for other := range s.entries {
+		if other == k || strings.HasSuffix(other, ","+k) {
+			delete(s.entries, other)
+		}
+	}
+	return true
+}
+
+// Fail adds a fault, which applies to requests received from then on. Faults are matched in the
+// order they were added.
+func (s *Server) Fail(fault Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes every fault.
+func (s *Server) ClearFaults() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.faults = nil
+}
+
+// fault returns the error of the first fault matching operation on dn, if any.
+func (s *Server) fault(operation string, dn string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i, f := range s.faults {
+		if f.Operation != "" && f.Operation != operation || f.DN != "" && key(f.DN) != key(dn) {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		message := f.Message
+		if message == "" {
+			message = ldap.LDAPResultCodeMap[f.ResultCode]
+		}
+		return ldap.NewError(f.ResultCode, errors.New(message))
+	}
+	return nil
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		s.mutex.Lock()
+		delete(s.conns, conn)
+		s.mutex.Unlock()
+		conn.Close()
+	}()
+	bound := false
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id, _ := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+		var controls []ldap.Control
+		if len(packet.Children) > 2 {
+			for _, child := range packet.Children[2].Children {
+				if control, err := ldap.DecodeControl(child); err == nil {
+					controls = append(controls, control)
+				}
+			}
+		}
+		var responses []*ber.Packet
+		var responseControl ldap.Control
+		switch request.Tag {
+		case ldap.ApplicationUnbindRequest:
+			return
+		case ldap.ApplicationAbandonRequest:
+			continue
+		case ldap.ApplicationBindRequest:
+			err = s.fault(OperationBind, request.Children[1].Data.String())
+			if err == nil {
+				err = s.bind(request)
+			}
+			bound = err == nil
+			responses = []*ber.Packet{result(ldap.ApplicationBindResponse, err)}
+		case ldap.ApplicationSearchRequest:
+			var entries []*ber.Packet
+			err = s.fault(OperationSearch, request.Children[0].Data.String())
+			if err == nil {
+				entries, responseControl, err = s.search(request, controls)
+			}
+			responses = append(entries, result(ldap.ApplicationSearchResultDone, err))
+		case ldap.ApplicationAddRequest:
+			responses = []*ber.Packet{result(ldap.ApplicationAddResponse, s.write(bound, OperationAdd, request.Children[0].Data.String(), func() error {
+				return s.add(request)
+			}))}
+		case ldap.ApplicationModifyRequest:
+			responses = []*ber.Packet{result(ldap.ApplicationModifyResponse, s.write(bound, OperationModify, request.Children[0].Data.String(), func() error {
+				return s.modify(request)
+			}))}
+		case ldap.ApplicationModifyDNRequest:
+			responses = []*ber.Packet{result(ldap.ApplicationModifyDNResponse, s.write(bound, OperationModifyDN, request.Children[0].Data.String(), func() error {
+				return s.modifyDN(request)
+			}))}
+		case ldap.ApplicationDelRequest:
+			responses = []*ber.Packet{result(ldap.ApplicationDelResponse, s.write(bound, OperationDelete, request.Data.String(), func() error {
+				return s.del(request.Data.String(), controls)
+			}))}
+		default:
+			err = ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("unsupported operation"))
+			responses = []*ber.Packet{result(ldap.ApplicationExtendedResponse, err)}
+		}
+		for i, response := range responses {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+			envelope.AppendChild(response)
+			if responseControl != nil && i == len(responses)-1 {
+				controls := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+				controls.AppendChild(responseControl.Encode())
+				envelope.AppendChild(controls)
+			}
+			if _, err := conn.Write(envelope.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) bind(request *ber.Packet) error {
+	dn := request.Children[1].Data.String()
+	password := request.Children[2].Data.String()
+	if dn == "" && password == "" {
+		return nil
+	}
+	if key(dn) != key(s.Config.BindDN) || password != s.Config.BindPassword {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	return nil
+}
+
+// search returns the encoded entries of a search request and the paged results control of the response,
+// if the request had one.
+func (s *Server) search(request *ber.Packet, controls []ldap.Control) ([]*ber.Packet, ldap.Control, error) {
+	base := request.Children[0].Data.String()
+	scope := int(request.Children[1].Value.(int64))
+	sizeLimit := int(request.Children[3].Value.(int64))
+	filter := request.Children[6]
+	selected := make([]string, 0)
+	for _, attribute := range request.Children[7].Children {
+		selected = append(selected, attribute.Data.String())
+	}
+	if base == "" && scope == ldap.ScopeBaseObject {
+		return []*ber.Packet{s.searchEntry(s.rootDSE(), selected)}, nil, nil
+	}
+	if _, err := ldap.ParseDN(base); err != nil {
+		return nil, nil, ldap.NewError(ldap.LDAPResultInvalidDNSyntax, err)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	baseKey := key(base)
+	if _, ok := s.entries[baseKey]; !ok && (base != "" || scope != ldap.ScopeWholeSubtree) {
+		return nil, nil, s.noSuchObject(base)
+	}
+	keys := make([]string, 0, len(s.entries))
+	for k := range s.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	matched := make([]*entry, 0)
+	for _, k := range keys {
+		e := s.entries[k]
+		inScope := false
+		switch scope {
+		case ldap.ScopeBaseObject:
+			inScope = k == baseKey
+		case ldap.ScopeSingleLevel:
+			_, parent := splitDN(e.dn)
+			inScope = key(parent) == baseKey
+		case ldap.ScopeWholeSubtree:
+			inScope = baseKey == "" || k == baseKey || strings.HasSuffix(k, ","+baseKey)
+		}
+		if !inScope {
+			continue
+		}
+		matches, err := match(filter, e)
+		if err != nil {
+			return nil, nil, err
+		}
+		if matches {
+			matched = append(matched, e)
+		}
+	}
+	var paging *ldap.ControlPaging
+	for _, control := range controls {
+		if c, ok := control.(*ldap.ControlPaging); ok {
+			paging = c
+		}
+	}
+	var err error
+	var response ldap.Control
+	if paging != nil {
+		matched, response, err = s.page(matched, paging)
+	} else {
+		limit := s.Config.MaxPageSize
+		if sizeLimit > 0 && (limit == 0 || sizeLimit < limit) {
+			limit = sizeLimit
+		}
+		if limit > 0 && len(matched) > limit {
+			matched = matched[:limit]
+			err = ldap.NewError(ldap.LDAPResultSizeLimitExceeded, fmt.Errorf("size limit of %d entries exceeded", limit))
+		}
+	}
+	results := make([]*ber.Packet, len(matched))
+	for i, e := range matched {
+		results[i] = s.searchEntry(e, selected)
+	}
+	return results, response, err
+}
+
+// page returns the page of entries requested by a paged results control, and the control of the
+// response, whose cookie is the offset of the next page.
+func (s *Server) page(entries []*entry, paging *ldap.ControlPaging) ([]*entry, ldap.Control, error) {
+	offset := 0
+	if len(paging.Cookie) > 0 {
+		var err error
+		offset, err = strconv.Atoi(string(paging.Cookie))
+		if err != nil || offset > len(entries) {
+			return nil, nil, ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("invalid paged results cookie"))
+		}
+	}
+	response := ldap.NewControlPaging(0)
+	size := int(paging.PagingSize)
+	if size == 0 { // A size of 0 abandons the search
+		return nil, response, nil
+	}
+	if s.Config.MaxPageSize > 0 && size > s.Config.MaxPageSize {
+		size = s.Config.MaxPageSize
+	}
+	end := offset + size
+	if end < len(entries) {
+		response.SetCookie([]byte(strconv.Itoa(end)))
+	} else {
+		end = len(entries)
+	}
+	return entries[offset:end], response, nil
+}
+
+func (s *Server) rootDSE() *entry {
+	attributes := map[string][]string{
+		"objectClass":          {"top"},
+		"namingContexts":       {s.Config.Suffix},
+		"defaultNamingContext": {s.Config.Suffix},
+		"supportedControl":     {ldap.ControlTypePaging},
+		"supportedLDAPVersion": {"3"},
+		"vendorName":           {"ldaptest"},
+	}
+	if s.Config.ActiveDirectory {
+		attributes["forestFunctionality"] = []string{"7"}
+		attributes["domainFunctionality"] = []string{"7"}
+		attributes["rootDomainNamingContext"] = []string{s.Config.Suffix}
+		attributes["supportedCapabilities"] = []string{capabilityActiveDirectory}
+		attributes["supportedControl"] = append(attributes["supportedControl"], controlTypeTreeDelete)
+		delete(attributes, "vendorName")
+	}
+	return &entry{attributes: attributes}
+}
+
+func (s *Server) write(bound bool, operation string, dn string, fn func() error) error {
+	if err := s.fault(operation, dn); err != nil {
+		return err
+	}
+	if !bound {
+		return ldap.NewError(ldap.LDAPResultInsufficientAccessRights, errors.New("anonymous writes are not allowed"))
+	}
+	return fn()
+}
+
+func (s *Server) add(request *ber.Packet) error {
+	dn := request.Children[0].Data.String()
+	attributes := make(map[string][]string)
+	for _, attribute := range request.Children[1].Children {
+		name := attribute.Children[0].Data.String()
+		for _, value := range attribute.Children[1].Children {
+			attributes[name] = append(attributes[name], value.Data.String())
+		}
+	}
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return ldap.NewError(ldap.LDAPResultInvalidDNSyntax, fmt.Errorf("invalid DN %q", dn))
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.entries[key(dn)]; ok {
+		return ldap.NewError(ldap.LDAPResultEntryAlreadyExists, errors.New("entry already exists"))
+	}
+	_, parent := splitDN(dn)
+	if _, ok := s.entries[key(parent)]; !ok && key(dn) != key(s.Config.Suffix) { // The suffix entry has no parent
+		return s.noSuchObject(parent)
+	}
+	if len(attributes["objectClass"]) == 0 {
+		return ldap.NewError(ldap.LDAPResultObjectClassViolation, errors.New("no objectClass attribute"))
+	}
+	for _, attribute := range parsed.RDNs[0].Attributes { // The naming attribute holds its value
+		addValues(attributes, attribute.Type, []string{attribute.Value})
+	}
+	s.entries[key(dn)] = &entry{dn: dn, attributes: attributes}
+	return nil
+}
+
+func (s *Server) modify(request *ber.Packet) error {
+	dn := request.Children[0].Data.String()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	e, ok := s.entries[key(dn)]
+	if !ok {
+		return s.noSuchObject(dn)
+	}
+	attributes := make(map[string][]string, len(e.attributes))
+	for name, values := range e.attributes {
+		attributes[name] = values
+	}
+	for _, change := range request.Children[1].Children {
+		operation := change.Children[0].Value.(int64)
+		name := change.Children[1].Children[0].Data.String()
+		values := make([]string, 0)
+		for _, value := range change.Children[1].Children[1].Children {
+			values = append(values, value.Data.String())
+		}
+		k, exists := lookup(attributes, name)
+		switch operation {
+		case ldap.AddAttribute:
+			for _, value := range values {
+				if exists && containsFold(attributes[k], value) {
+					return ldap.NewError(ldap.LDAPResultAttributeOrValueExists, fmt.Errorf("%s already holds %q", name, value))
+				}
+			}
+			addValues(attributes, name, values)
+		case ldap.DeleteAttribute:
+			if !exists {
+				return ldap.NewError(ldap.LDAPResultNoSuchAttribute, fmt.Errorf("no attribute %s", name))
+			}
+			if len(values) == 0 {
+				delete(attributes, k)
+				continue
+			}
+			remaining := make([]string, 0)
+			for _, value := range attributes[k] {
+				if !containsFold(values, value) {
+					remaining = append(remaining, value)
+				}
+			}
+			if len(remaining) == len(attributes[k]) {
+				return ldap.NewError(ldap.LDAPResultNoSuchAttribute, fmt.Errorf("%s does not hold %v", name, values))
+			}
+			attributes[k] = remaining
+			if len(remaining) == 0 {
+				delete(attributes, k)
+			}
+		case ldap.ReplaceAttribute:
+			if exists {
+				delete(attributes, k)
+			}
+			if len(values) > 0 {
+				attributes[name] = values
+			}
+		default:
+			return ldap.NewError(ldap.LDAPResultUnwillingToPerform, fmt.Errorf("unsupported modify operation %d", operation))
+		}
+	}
+	e.attributes = attributes
+	return nil
+}
+
+func (s *Server) modifyDN(request *ber.Packet) error {
+	dn := request.Children[0].Data.String()
+	newRDN := request.Children[1].Data.String()
+	deleteOldRDN := request.Children[2].Value.(bool)
+	_, parent := splitDN(dn)
+	if len(request.Children) > 3 {
+		parent = request.Children[3].Data.String()
+	}
+	newDN := newRDN + "," + parent
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	e, ok := s.entries[key(dn)]
+	if !ok {
+		return s.noSuchObject(dn)
+	}
+	if _, ok := s.entries[key(parent)]; !ok {
+		return s.noSuchObject(parent)
+	}
+	if _, ok := s.entries[key(newDN)]; ok {
+		return ldap.NewError(ldap.LDAPResultEntryAlreadyExists, errors.New("entry already exists"))
+	}
+	oldRDN, _ := ldap.ParseDN(dn)
+	parsedRDN, err := ldap.ParseDN(newRDN)
+	if err != nil || len(parsedRDN.RDNs) != 1 {
+		return ldap.NewError(ldap.LDAPResultInvalidDNSyntax, fmt.Errorf("invalid RDN %q", newRDN))
+	}
+	if deleteOldRDN {
+		for _, attribute := range oldRDN.RDNs[0].Attributes {
+			if k, ok := lookup(e.attributes, attribute.Type); ok {
+				remaining := make([]string, 0)
+				for _, value := range e.attributes[k] {
+					if !strings.EqualFold(value, attribute.Value) {
+						remaining = append(remaining, value)
+					}
+				}
+				e.attributes[k] = remaining
+			}
+		}
+	}
+	for _, attribute := range parsedRDN.RDNs[0].Attributes {
+		addValues(e.attributes, attribute.Type, []string{attribute.Value})
+	}
+	// Rename the entry and its descendants
+	oldSuffix := key(dn)
+	for k, descendant := range s.entries {
+		if k == oldSuffix || strings.HasSuffix(k, ","+oldSuffix) {
+			delete(s.entries, k)
+			descendant.dn = descendant.dn[:len(descendant.dn)-len(dn)] + newDN
+			s.entries[key(descendant.dn)] = descendant
+		}
+	}
+	return nil
+}
+
+func (s *Server) del(dn string, controls []ldap.Control) error {
+	tree := false
+	for _, control := range controls {
+		if control.GetControlType() == controlTypeTreeDelete {
+			if !s.Config.ActiveDirectory {
+				return ldap.NewError(ldap.LDAPResultUnavailableCriticalExtension, errors.New("tree delete control is not supported"))
+			}
+			tree = true
+		}
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	k := key(dn)
+	if _, ok := s.entries[k]; !ok {
+		return s.noSuchObject(dn)
+	}
+	for other := range s.entries {
+		if strings.HasSuffix(other, ","+k) {
+			if !tree {
+				return ldap.NewError(ldap.LDAPResultNotAllowedOnNonLeaf, errors.New("entry has children"))
+			}
+			delete(s.entries, other)
+		}
+	}
+	delete(s.entries, k)
+	return nil
+}
+
+// noSuchObject returns a No Such Object error naming the closest existing ancestor of dn.
+func (s *Server) noSuchObject(dn string) error {
+	matched := dn
+	for matched != "" {
+		if _, ok := s.entries[key(matched)]; ok {
+			break
+		}
+		_, matched = splitDN(matched)
+	}
+	return &ldap.Error{ResultCode: ldap.LDAPResultNoSuchObject, MatchedDN: matched, Err: fmt.Errorf("no such object %q", dn)}
+}
+
+// searchEntry encodes a SearchResultEntry holding the selected attributes of e. Attributes with more
+// values than MaxValRange, and attributes selected with a range option, are returned by range.
+func (s *Server) searchEntry(e *entry, selected []string) *ber.Packet {
+	all := len(selected) == 0 || containsFold(selected, "*")
+	names := make([]string, 0, len(e.attributes))
+	for name := range e.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "Object Name"))
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for _, name := range names {
+		low, high, ok := 0, -1, all
+		for _, description := range selected {
+			if l, h, isRange := parseRange(description); strings.EqualFold(strings.SplitN(description, ";", 2)[0], name) {
+				ok = true
+				if isRange {
+					low, high = l, h
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		attributeType, values := name, e.attributes[name]
+		if ranged := high >= 0 || low > 0; ranged || s.Config.MaxValRange > 0 && len(values) > s.Config.MaxValRange {
+			attributeType, values = s.valueRange(name, values, low, high)
+		}
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attributeType, "Type"))
+		vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			vals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attribute.AppendChild(vals)
+		attributes.AppendChild(attribute)
+	}
+	response.AppendChild(attributes)
+	return response
+}
+
+// valueRange returns the values from low to high, or to the last value if high is -1, limited to
+// MaxValRange values, and the attribute type naming the range returned, e.g. "member;range=0-1499".
+// The upper bound is "*" when the range includes the last value.
+func (s *Server) valueRange(name string, values []string, low int, high int) (string, []string) {
+	if low >= len(values) {
+		return fmt.Sprintf("%s;range=%d-*", name, low), nil
+	}
+	end := len(values) - 1
+	if high >= 0 && high < end {
+		end = high
+	}
+	if s.Config.MaxValRange > 0 && end-low+1 > s.Config.MaxValRange {
+		end = low + s.Config.MaxValRange - 1
+	}
+	bound := strconv.Itoa(end)
+	if end == len(values)-1 {
+		bound = "*"
+	}
+	return fmt.Sprintf("%s;range=%d-%s", name, low, bound), values[low : end+1]
+}
+
+// parseRange parses the range option of an attribute description, e.g. "member;range=1500-*", returning
+// -1 as the upper bound for "*".
+func parseRange(description string) (low int, high int, ok bool) {
+	for _, option := range strings.Split(description, ";")[1:] {
+		if !strings.HasPrefix(strings.ToLower(option), "range=") {
+			continue
+		}
+		bounds := strings.SplitN(option[len("range="):], "-", 2)
+		if len(bounds) != 2 {
+			return 0, -1, false
+		}
+		low, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return 0, -1, false
+		}
+		high := -1
+		if bounds[1] != "*" {
+			if high, err = strconv.Atoi(bounds[1]); err != nil || high < low {
+				return 0, -1, false
+			}
+		}
+		return low, high, true
+	}
+	return 0, -1, false
+}
+
+// result encodes an LDAPResult of the application type tag for err.
+func result(tag ber.Tag, err error) *ber.Packet {
+	code, matchedDN, message := uint16(ldap.LDAPResultSuccess), "", ""
+	if err != nil {
+		code, message = ldap.LDAPResultOther, err.Error()
+		if e, ok := err.(*ldap.Error); ok {
+			code, matchedDN, message = e.ResultCode, e.MatchedDN, e.Err.Error()
+		}
+	}
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, matchedDN, "Matched DN"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Diagnostic Message"))
+	return response
+}
+
+// key returns dn normalized for comparison, ignoring case, spacing and the order of multi-valued RDNs.
+func key(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(dn)
+	}
+	rdns := make([]string, len(parsed.RDNs))
+	for i, rdn := range parsed.RDNs {
+		values := make([]string, len(rdn.Attributes))
+		for j, attr := range rdn.Attributes {
+			values[j] = strings.ToLower(attr.Type + "=" + attr.Value)
+		}
+		sort.Strings(values)
+		rdns[i] = strings.Join(values, "+")
+	}
+	return strings.Join(rdns, ",")
+}
+
+// splitDN splits dn into its RDN and the DN of its parent.
+func splitDN(dn string) (rdn string, parent string) {
+	for i := 0; i < len(dn); i++ {
+		switch dn[i] {
+		case '\\':
+			i++
+		case ',':
+			return dn[:i], dn[i+1:]
+		}
+	}
+	return dn, ""
+}
+
+func lookup(attributes map[string][]string, name string) (string, bool) {
+	for k, values := range attributes {
+		if strings.EqualFold(k, name) && len(values) > 0 {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+func addValues(attributes map[string][]string, name string, values []string) {
+	k, ok := lookup(attributes, name)
+	if !ok {
+		k = name
+	}
+	for _, value := range values {
+		if !containsFold(attributes[k], value) {
+			attributes[k] = append(attributes[k], value)
+		}
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package ldaptest
+
+import (
+	"fmt"
+	"github.com/go-ldap/ldap/v3"
+	"testing"
+)
+
+func newTestServer(t *testing.T, config Config) (*Server, *ldap.Conn) {
+	s, err := NewServer(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	conn, err := ldap.DialURL(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(conn.Close)
+	if err := conn.Bind(s.Config.BindDN, s.Config.BindPassword); err != nil {
+		t.Fatal(err)
+	}
+	return s, conn
+}
+
+func TestServer_bind(t *testing.T) {
+	s, conn := newTestServer(t, Config{})
+	if err := conn.Bind(s.Config.BindDN, "wrong"); !ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		t.Errorf("bind with a wrong password returned %v, expected Invalid Credentials", err)
+	}
+	request := ldap.NewAddRequest("ou=People,dc=example,dc=com", nil)
+	request.Attribute("objectClass", []string{"organizationalUnit"})
+	if err := conn.Add(request); !ldap.IsErrorWithCode(err, ldap.LDAPResultInsufficientAccessRights) {
+		t.Errorf("anonymous add returned %v, expected Insufficient Access Rights", err)
+	}
+}
+
+func TestServer_entries(t *testing.T) {
+	s, conn := newTestServer(t, Config{})
+	request := ldap.NewAddRequest("ou=People,dc=example,dc=com", nil)
+	request.Attribute("objectClass", []string{"top", "organizationalUnit"})
+	request.Attribute("description", []string{"Staff"})
+	if err := conn.Add(request); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Add(request); !ldap.IsErrorWithCode(err, ldap.LDAPResultEntryAlreadyExists) {
+		t.Errorf("second add returned %v, expected Entry Already Exists", err)
+	}
+	if attributes := s.Get("OU=people, DC=Example, DC=com"); len(attributes["ou"]) != 1 || attributes["ou"][0] != "People" {
+		t.Errorf("entry is %q, expected the naming attribute to be added", attributes)
+	}
+
+	modify := ldap.NewModifyRequest("ou=People,dc=example,dc=com", nil)
+	modify.Replace("description", []string{"Staff accounts"})
+	modify.Add("l", []string{"Springfield"})
+	if err := conn.Modify(modify); err != nil {
+		t.Fatal(err)
+	}
+	result, err := conn.Search(ldap.NewSearchRequest("dc=example,dc=com", ldap.ScopeWholeSubtree, 0, 0, 0, false,
+		"(&(objectClass=organizationalUnit)(l=spring*))", []string{"description"}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Entries) != 1 || result.Entries[0].GetAttributeValue("description") != "Staff accounts" {
+		t.Errorf("search returned %d entries, expected the modified entry", len(result.Entries))
+	}
+
+	if err := conn.ModifyDN(ldap.NewModifyDNRequest("ou=People,dc=example,dc=com", "ou=Staff", true, "")); err != nil {
+		t.Fatal(err)
+	}
+	if s.Get("ou=People,dc=example,dc=com") != nil || s.Get("ou=Staff,dc=example,dc=com") == nil {
+		t.Error("modify DN did not rename the entry")
+	}
+	if err := conn.Del(ldap.NewDelRequest("ou=Staff,dc=example,dc=com", nil)); err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.Search(ldap.NewSearchRequest("ou=Staff,dc=example,dc=com", ldap.ScopeBaseObject, 0, 0, 0, false,
+		"(objectClass=*)", nil, nil))
+	if e, ok := err.(*ldap.Error); !ok || e.ResultCode != ldap.LDAPResultNoSuchObject || e.MatchedDN != "dc=example,dc=com" {
+		t.Errorf("search of a deleted entry returned %v, expected No Such Object matching the suffix", err)
+	}
+}
+
+func TestServer_addSuffix(t *testing.T) {
+	s, conn := newTestServer(t, Config{})
+	s.Delete("dc=example,dc=com")
+	request := ldap.NewAddRequest("dc=example,dc=com", nil)
+	request.Attribute("objectClass", []string{"domain"})
+	if err := conn.Add(request); err != nil {
+		t.Errorf("add of the suffix entry returned %v, expected it to need no parent", err)
+	}
+	request = ldap.NewAddRequest("dc=example,dc=org", nil)
+	request.Attribute("objectClass", []string{"domain"})
+	if err := conn.Add(request); !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		t.Errorf("add of an entry without a parent returned %v, expected No Such Object", err)
+	}
+}
+
+func TestServer_searchEmptyBase(t *testing.T) {
+	s, conn := newTestServer(t, Config{})
+	s.Put("dc=child,dc=example,dc=org", map[string][]string{"objectClass": {"domain"}})
+	request := ldap.NewSearchRequest("", ldap.ScopeWholeSubtree, 0, 0, 0, false, "(objectClass=domain)", nil, nil)
+	result, err := conn.Search(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Entries) != 2 {
+		t.Errorf("subtree search of the empty base returned %d entries, expected both naming contexts", len(result.Entries))
+	}
+	request.Scope = ldap.ScopeSingleLevel
+	if _, err := conn.Search(request); !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		t.Errorf("one-level search of the empty base returned %v, expected No Such Object", err)
+	}
+}
+
+func TestServer_treeDelete(t *testing.T) {
+	for _, activeDirectory := range []bool{false, true} {
+		s, conn := newTestServer(t, Config{ActiveDirectory: activeDirectory})
+		s.Put("ou=People,dc=example,dc=com", map[string][]string{"objectClass": {"organizationalUnit"}})
+		s.Put("uid=alice,ou=People,dc=example,dc=com", map[string][]string{"objectClass": {"person"}})
+		if err := conn.Del(ldap.NewDelRequest("ou=People,dc=example,dc=com", nil)); !ldap.IsErrorWithCode(err, ldap.LDAPResultNotAllowedOnNonLeaf) {
+			t.Errorf("delete of a non-leaf returned %v, expected Not Allowed On Non-Leaf", err)
+		}
+		request := ldap.NewDelRequest("ou=People,dc=example,dc=com", []ldap.Control{ldap.NewControlString(controlTypeTreeDelete, true, "")})
+		err := conn.Del(request)
+		if activeDirectory && err != nil {
+			t.Errorf("tree delete returned %v", err)
+		} else if !activeDirectory && !ldap.IsErrorWithCode(err, ldap.LDAPResultUnavailableCriticalExtension) {
+			t.Errorf("tree delete returned %v, expected Unavailable Critical Extension", err)
+		}
+	}
+}
+
+func TestServer_fault(t *testing.T) {
+	s, conn := newTestServer(t, Config{})
+	s.Fail(Fault{Operation: OperationSearch, DN: "dc=example,dc=com", ResultCode: ldap.LDAPResultBusy, Count: 2})
+	request := ldap.NewSearchRequest("dc=example,dc=com", ldap.ScopeBaseObject, 0, 0, 0, false, "(objectClass=*)", nil, nil)
+	for i := 0; i < 2; i++ {
+		if _, err := conn.Search(request); !ldap.IsErrorWithCode(err, ldap.LDAPResultBusy) {
+			t.Errorf("search %d returned %v, expected Busy", i+1, err)
+		}
+	}
+	if _, err := conn.Search(request); err != nil {
+		t.Errorf("search after the fault was used up returned %v", err)
+	}
+
+	s.Fail(Fault{Operation: OperationModify, ResultCode: ldap.LDAPResultConstraintViolation, Message: "0000052D: Constraint violation"})
+	modify := ldap.NewModifyRequest("dc=example,dc=com", nil)
+	modify.Replace("description", []string{"Example"})
+	for i := 0; i < 3; i++ {
+		if err := conn.Modify(modify); !ldap.IsErrorWithCode(err, ldap.LDAPResultConstraintViolation) {
+			t.Errorf("modify %d returned %v, expected Constraint Violation", i+1, err)
+		}
+	}
+	s.ClearFaults()
+	if err := conn.Modify(modify); err != nil {
+		t.Errorf("modify after clearing faults returned %v", err)
+	}
+}
+
+func TestServer_paging(t *testing.T) {
+	s, conn := newTestServer(t, Config{MaxPageSize: 10})
+	for i := 0; i < 25; i++ {
+		s.Put(fmt.Sprintf("cn=user%02d,dc=example,dc=com", i), map[string][]string{"objectClass": {"person"}})
+	}
+	request := ldap.NewSearchRequest("dc=example,dc=com", ldap.ScopeSingleLevel, 0, 0, 0, false, "(objectClass=person)", []string{"cn"}, nil)
+	result, err := conn.Search(request)
+	if !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		t.Errorf("search without paging returned %v, expected Size Limit Exceeded", err)
+	}
+	if result == nil || len(result.Entries) != 10 {
+		t.Errorf("search without paging returned %v, expected the first 10 entries", result)
+	}
+	result, err = conn.SearchWithPaging(request, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Entries) != 25 {
+		t.Errorf("paged search returned %d entries, expected 25", len(result.Entries))
+	}
+}
+
+func TestServer_rangeRetrieval(t *testing.T) {
+	s, conn := newTestServer(t, Config{ActiveDirectory: true, MaxValRange: 10})
+	members := make([]string, 25)
+	for i := range members {
+		members[i] = fmt.Sprintf("cn=user%02d,dc=example,dc=com", i)
+	}
+	s.Put("cn=staff,dc=example,dc=com", map[string][]string{"objectClass": {"group"}, "member": members})
+	read := func(attribute string) *ldap.EntryAttribute {
+		request := ldap.NewSearchRequest("cn=staff,dc=example,dc=com", ldap.ScopeBaseObject, 0, 0, 0, false, "(objectClass=*)",
+			[]string{attribute}, nil)
+		result, err := conn.Search(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Entries) != 1 || len(result.Entries[0].Attributes) != 1 {
+			t.Fatalf("search for %s returned %v, expected one attribute", attribute, result.Entries)
+		}
+		return result.Entries[0].Attributes[0]
+	}
+	cases := []struct {
+		attribute string
+		name      string
+		first     string
+		count     int
+	}{
+		{"member", "member;range=0-9", members[0], 10},
+		{"member;range=10-*", "member;range=10-19", members[10], 10},
+		{"member;range=20-*", "member;range=20-*", members[20], 5},
+		{"member;range=5-7", "member;range=5-7", members[5], 3},
+	}
+	for _, c := range cases {
+		attribute := read(c.attribute)
+		if attribute.Name != c.name || len(attribute.Values) != c.count || attribute.Values[0] != c.first {
+			t.Errorf("search for %s returned %s with %d values, expected %s with %d values", c.attribute, attribute.Name,
+				len(attribute.Values), c.name, c.count)
+		}
+	}
+	if attribute := read("objectClass"); attribute.Name != "objectClass" {
+		t.Errorf("search for objectClass returned %s, expected no range", attribute.Name)
+	}
+}
+
+func TestServer_rootDSE(t *testing.T) {
+	for _, activeDirectory := range []bool{false, true} {
+		_, conn := newTestServer(t, Config{ActiveDirectory: activeDirectory})
+		request := ldap.NewSearchRequest("", ldap.ScopeBaseObject, 0, 0, 0, false, "(objectClass=*)", nil, nil)
+		result, err := conn.Search(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry := result.Entries[0]
+		if entry.GetAttributeValue("defaultNamingContext") != DefaultSuffix {
+			t.Errorf("defaultNamingContext is %q, expected %q", entry.GetAttributeValue("defaultNamingContext"), DefaultSuffix)
+		}
+		if actual := entry.GetAttributeValue("supportedCapabilities") == capabilityActiveDirectory; actual != activeDirectory {
+			t.Errorf("root DSE advertises Active Directory: %t, expected %t", actual, activeDirectory)
+		}
+	}
+}
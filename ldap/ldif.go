@@ -0,0 +1,152 @@
+package ldap
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/go-ldap/ldap/v3"
+	"os"
+	"strings"
+	"time"
+)
+
+const ldifLineLength = 76
+
+// writeLDIF appends the RFC 2849 change record of an applied change to the LDIF output file, if one is
+// configured.
+func (c *Client) writeLDIF(record string) error {
+	if err := c.appendLDIF("", record); err != nil {
+		return fmt.Errorf("the change was applied but could not be written to the LDIF output file: %v", err)
+	}
+	return nil
+}
+
+// appendLDIF appends record to the LDIF output file, if one is configured, preceded by a comment with the
+// time, the server and note.
+func (c *Client) appendLDIF(note string, record string) error {
+	if c.LDIFOutputFile == "" {
+		return nil
+	}
+	c.shared.ldifMutex.Lock()
+	defer c.shared.ldifMutex.Unlock()
+	file, err := os.OpenFile(c.LDIFOutputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var b strings.Builder
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		b.WriteString("version: 1\n")
+	}
+	b.WriteString("\n")
+	comment := fmt.Sprintf("# %s %s", time.Now().UTC().Format(time.RFC3339), c.Server)
+	if note != "" {
+		comment += " (" + note + ")"
+	}
+	b.WriteString(comment + "\n")
+	b.WriteString(record)
+	_, err = file.WriteString(b.String())
+	return err
+}
+
+func ldifAddRecord(request *ldap.AddRequest) string {
+	var b strings.Builder
+	ldifWriteLine(&b, "dn", request.DN)
+	ldifWriteControls(&b, request.Controls)
+	b.WriteString("changetype: add\n")
+	for _, attribute := range request.Attributes {
+		for _, value := range attribute.Vals {
+			ldifWriteLine(&b, attribute.Type, value)
+		}
+	}
+	return b.String()
+}
+
+func ldifModifyRecord(request *ldap.ModifyRequest) string {
+	operations := map[uint]string{
+		ldap.AddAttribute:       "add",
+		ldap.DeleteAttribute:    "delete",
+		ldap.ReplaceAttribute:   "replace",
+		ldap.IncrementAttribute: "increment",
+	}
+	var b strings.Builder
+	ldifWriteLine(&b, "dn", request.DN)
+	ldifWriteControls(&b, request.Controls)
+	b.WriteString("changetype: modify\n")
+	for _, change := range request.Changes {
+		ldifWriteLine(&b, operations[change.Operation], change.Modification.Type)
+		for _, value := range change.Modification.Vals {
+			ldifWriteLine(&b, change.Modification.Type, value)
+		}
+		b.WriteString("-\n")
+	}
+	return b.String()
+}
+
+func ldifModifyDNRecord(request *ldap.ModifyDNRequest) string {
+	var b strings.Builder
+	ldifWriteLine(&b, "dn", request.DN)
+	b.WriteString("changetype: modrdn\n")
+	ldifWriteLine(&b, "newrdn", request.NewRDN)
+	if request.DeleteOldRDN {
+		b.WriteString("deleteoldrdn: 1\n")
+	} else {
+		b.WriteString("deleteoldrdn: 0\n")
+	}
+	if request.NewSuperior != "" {
+		ldifWriteLine(&b, "newsuperior", request.NewSuperior)
+	}
+	return b.String()
+}
+
+func ldifDeleteRecord(request *ldap.DelRequest) string {
+	var b strings.Builder
+	ldifWriteLine(&b, "dn", request.DN)
+	ldifWriteControls(&b, request.Controls)
+	b.WriteString("changetype: delete\n")
+	return b.String()
+}
+
+func ldifWriteControls(b *strings.Builder, controls []ldap.Control) {
+	for _, control := range controls {
+		line := control.GetControlType()
+		if c, ok := control.(*ldap.ControlString); ok {
+			line = fmt.Sprintf("%s %t", line, c.Criticality)
+			if c.ControlValue != "" {
+				ldifWriteLine(b, "control", line+": "+c.ControlValue)
+				continue
+			}
+		}
+		ldifWriteLine(b, "control", line)
+	}
+}
+
+// ldifWriteLine writes an attribute-value line, base64-encoding values that are not SAFE-STRINGs and
+// folding lines longer than 76 characters.
+func ldifWriteLine(b *strings.Builder, name string, value string) {
+	line := name + ": " + value
+	if !ldifSafeString(value) {
+		line = name + ":: " + base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	for width := ldifLineLength; len(line) > width; width = ldifLineLength - 1 {
+		b.WriteString(line[:width])
+		b.WriteString("\n ")
+		line = line[width:]
+	}
+	b.WriteString(line)
+	b.WriteString("\n")
+}
+
+func ldifSafeString(value string) bool {
+	if value == "" {
+		return true
+	}
+	if value[0] == ' ' || value[0] == ':' || value[0] == '<' || value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] == 0 || value[i] == '\n' || value[i] == '\r' || value[i] > 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// testAccLdifOutputFile returns the path of an LDIF output file in a directory that is removed when the test ends.
+func testAccLdifOutputFile(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ldif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "changes.ldif")
+}
+
+// testAccCheckLdifOutputFile checks that the LDIF output file at path contains each of records.
+func testAccCheckLdifOutputFile(t *testing.T, path string, records ...string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		if !strings.Contains(string(content), record) {
+			t.Errorf("LDIF output file does not contain %q:\n%s", record, content)
+		}
+	}
+}
+
+func TestAccLdapProvider_ldifOutputFile(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	path := testAccLdifOutputFile(t)
+	dn := "ou=People,dc=example,dc=com"
+	config := func(description string) string {
+		return testAccProviderConfig(server, fmt.Sprintf("ldif_output_file = %q", path)) + `
+resource "ldap_organizational_unit" "people" {
+  ou          = "People"
+  path        = "dc=example,dc=com"
+  description = "` + description + `"
+}
+`
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: config("Employees"),
+			},
+			{
+				Config: config("Staff"),
+			},
+		},
+	})
+	testAccCheckLdifOutputFile(t, path,
+		"version: 1\n",
+		"dn: ou=People,dc=example,dc=com\nchangetype: add\n",
+		"dn: ou=People,dc=example,dc=com\nchangetype: modify\nreplace: description\ndescription: Staff\n-\n",
+		"dn: ou=People,dc=example,dc=com\nchangetype: delete\n",
+	)
+}
+
+func TestAccLdapProvider_readOnlyLdifOutputFile(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	path := testAccLdifOutputFile(t)
+	dn := "ou=People,dc=example,dc=com"
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server, "read_only = true", fmt.Sprintf("ldif_output_file = %q", path)) + `
+resource "ldap_organizational_unit" "people" {
+  ou   = "People"
+  path = "dc=example,dc=com"
+}
+`,
+				ExpectError: regexp.MustCompile(`refusing to add "ou=People,dc=example,dc=com"`),
+			},
+		},
+	})
+	testAccCheckLdifOutputFile(t, path,
+		"(not applied: read_only = true)\ndn: ou=People,dc=example,dc=com\nchangetype: add\n",
+	)
+}
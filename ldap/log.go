@@ -0,0 +1,123 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/go-ldap/ldap/v3"
+	"log"
+	"strings"
+	"time"
+)
+
+// The methods below send a request on conn and log it, with its duration and result, at the DEBUG
+// level shown when TF_LOG is DEBUG or TRACE. Passwords and other sensitive values are redacted.
+
+var searchScopes = map[int]string{
+	ldap.ScopeBaseObject:   "base",
+	ldap.ScopeSingleLevel:  "one",
+	ldap.ScopeWholeSubtree: "sub",
+}
+
+func (c *Client) bind(conn *ldap.Conn) error {
+	start := time.Now()
+	var err error
+	if c.BindPassword != "" {
+		err = conn.Bind(c.BindDN, c.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind(c.BindDN)
+	}
+	c.logRequest("bind", start, err, fmt.Sprintf("dn=%q", c.BindDN), fmt.Sprintf("password=%s", logPassword(c.BindPassword)))
+	return err
+}
+
+func (c *Client) search(conn *ldap.Conn, request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	start := time.Now()
+	result, err := conn.Search(request)
+	c.logRequest("search", start, err, logSearch(request, result)...)
+	return result, err
+}
+
+func (c *Client) searchWithPaging(conn *ldap.Conn, request *ldap.SearchRequest, pagingSize uint32) (*ldap.SearchResult, error) {
+	start := time.Now()
+	result, err := conn.SearchWithPaging(request, pagingSize)
+	c.logRequest("search", start, err, append(logSearch(request, result), fmt.Sprintf("page_size=%d", pagingSize))...)
+	return result, err
+}
+
+func (c *Client) add(conn *ldap.Conn, request *ldap.AddRequest) error {
+	start := time.Now()
+	err := conn.Add(request)
+	attributes := make([]string, len(request.Attributes))
+	for i, attribute := range request.Attributes {
+		attributes[i] = logValues(attribute.Type, attribute.Vals)
+	}
+	c.logRequest("add", start, err, fmt.Sprintf("dn=%q", request.DN), "attributes={"+strings.Join(attributes, ", ")+"}")
+	return err
+}
+
+func (c *Client) modify(conn *ldap.Conn, request *ldap.ModifyRequest) error {
+	start := time.Now()
+	err := conn.Modify(request)
+	changes := describeChanges(request)
+	for i, change := range changes {
+		changes[i] = strings.TrimSpace(change)
+	}
+	c.logRequest("modify", start, err, fmt.Sprintf("dn=%q", request.DN), "changes=["+strings.Join(changes, ", ")+"]")
+	return err
+}
+
+func (c *Client) modifyDN(conn *ldap.Conn, request *ldap.ModifyDNRequest) error {
+	start := time.Now()
+	err := conn.ModifyDN(request)
+	c.logRequest("modify DN", start, err, fmt.Sprintf("dn=%q", request.DN), fmt.Sprintf("new_rdn=%q", request.NewRDN),
+		fmt.Sprintf("delete_old_rdn=%t", request.DeleteOldRDN), fmt.Sprintf("new_superior=%q", request.NewSuperior))
+	return err
+}
+
+func (c *Client) del(conn *ldap.Conn, request *ldap.DelRequest) error {
+	start := time.Now()
+	err := conn.Del(request)
+	details := []string{fmt.Sprintf("dn=%q", request.DN)}
+	for _, control := range request.Controls {
+		details = append(details, fmt.Sprintf("control=%s", control.GetControlType()))
+	}
+	c.logRequest("delete", start, err, details...)
+	return err
+}
+
+// logRequest logs an operation that started at start and failed with err, if not nil.
+func (c *Client) logRequest(operation string, start time.Time, err error, details ...string) {
+	result := "Success (0)"
+	if err != nil {
+		result = err.Error()
+		if e, ok := err.(*ldap.Error); ok {
+			result = fmt.Sprintf("%s (%d): %s", ldap.LDAPResultCodeMap[e.ResultCode], e.ResultCode, e.Err)
+		}
+	}
+	fields := append(details, fmt.Sprintf("server=%s", c.Server), fmt.Sprintf("duration=%s", time.Since(start).Round(time.Microsecond)),
+		fmt.Sprintf("result=%q", result))
+	log.Printf("[DEBUG] LDAP %s %s", operation, strings.Join(fields, " "))
+}
+
+func logSearch(request *ldap.SearchRequest, result *ldap.SearchResult) []string {
+	details := []string{
+		fmt.Sprintf("base=%q", request.BaseDN),
+		fmt.Sprintf("scope=%s", searchScopes[request.Scope]),
+		fmt.Sprintf("filter=%q", request.Filter),
+		fmt.Sprintf("attributes=%v", request.Attributes),
+	}
+	if result != nil {
+		details = append(details, fmt.Sprintf("entries=%d", len(result.Entries)))
+	}
+	return details
+}
+
+func logValues(name string, values []string) string {
+	return fmt.Sprintf("%s: %q", name, redact(name, values))
+}
+
+func logPassword(password string) string {
+	if password == "" {
+		return "(none)"
+	}
+	return "(redacted)"
+}
@@ -4,10 +4,23 @@ const (
 	top = "top"
 )
 
+// resourceData is implemented by both schema.ResourceData and schema.ResourceDiff, so that objects
+// can be unmarshalled during plan as well as apply.
+type resourceData interface {
+	Get(key string) interface{}
+	GetChange(key string) (interface{}, interface{})
+	GetOk(key string) (interface{}, bool)
+	HasChange(key string) bool
+	Id() string
+}
+
 type Object interface {
 	GetObjectClass() []string
 	GetDN() string
 	GetRelativeDN() string
+	// GetNamingAttribute returns the attribute naming the object in its DN and its value, which is empty
+	// when it is not known until the entry is read, e.g. on import.
+	GetNamingAttribute() (attribute string, value string)
 	GetPath() string
 	GetAttributes() Attributes
 	SetAttributes(attributes Attributes)
@@ -0,0 +1,70 @@
+package ldap
+
+const (
+	ORGANIZATION = "organization"
+)
+
+type Organization struct {
+	City            string
+	Description     string
+	DN              string
+	ExtraAttributes Attributes
+	ObjectClass     []string
+	Organization    string
+	Path            string
+	PostalCode      string
+	State           string
+	StreetAddress   string
+	TelephoneNumber string
+}
+
+func (o *Organization) GetAttributes() Attributes {
+	attributes := Attributes{map[string][]string{
+		"l":               {o.City},
+		"description":     {o.Description},
+		"o":               {o.Organization},
+		"objectClass":     o.ObjectClass,
+		"postalCode":      {o.PostalCode},
+		"st":              {o.State},
+		"street":          {o.StreetAddress},
+		"telephoneNumber": {o.TelephoneNumber},
+	}}
+	attributes.Merge(o.ExtraAttributes)
+	return attributes
+}
+
+func (o *Organization) SetAttributes(attributes Attributes) {
+	o.City = attributes.GetFirst("l")
+	o.Description = attributes.GetFirst("description")
+	o.ExtraAttributes = attributes.Select(o.ExtraAttributes.Keys())
+	o.ObjectClass = attributes.Get("objectClass")
+	o.Organization = attributes.GetFirst("o")
+	o.PostalCode = attributes.GetFirst("postalCode")
+	o.State = attributes.GetFirst("st")
+	o.StreetAddress = attributes.GetFirst("street")
+	o.TelephoneNumber = attributes.GetFirst("telephoneNumber")
+}
+
+func (o *Organization) GetObjectClass() []string {
+	return o.ObjectClass
+}
+
+func (o *Organization) GetDN() string {
+	return o.DN
+}
+
+func (o *Organization) GetPath() string {
+	return o.Path
+}
+
+func (o *Organization) GetRelativeDN() string {
+	return "o=" + o.Organization
+}
+
+func (o *Organization) GetNamingAttribute() (string, string) {
+	return "o", o.Organization
+}
+
+func (o *Organization) SetDN(dn string) {
+	o.DN = dn
+}
@@ -0,0 +1,61 @@
+package ldap
+
+const (
+	ORGANIZATIONAL_ROLE = "organizationalRole"
+)
+
+type OrganizationalRole struct {
+	CommonName      string
+	Description     string
+	DN              string
+	ExtraAttributes Attributes
+	ObjectClass     []string
+	Path            string
+	RoleOccupants   []string
+	TelephoneNumber string
+}
+
+func (r *OrganizationalRole) GetAttributes() Attributes {
+	attributes := Attributes{map[string][]string{
+		"cn":              {r.CommonName},
+		"description":     {r.Description},
+		"objectClass":     r.ObjectClass,
+		"roleOccupant":    r.RoleOccupants,
+		"telephoneNumber": {r.TelephoneNumber},
+	}}
+	attributes.Merge(r.ExtraAttributes)
+	return attributes
+}
+
+func (r *OrganizationalRole) SetAttributes(attributes Attributes) {
+	r.CommonName = attributes.GetFirst("cn")
+	r.Description = attributes.GetFirst("description")
+	r.ExtraAttributes = attributes.Select(r.ExtraAttributes.Keys())
+	r.ObjectClass = attributes.Get("objectClass")
+	r.RoleOccupants = attributes.Get("roleOccupant")
+	r.TelephoneNumber = attributes.GetFirst("telephoneNumber")
+}
+
+func (r *OrganizationalRole) GetObjectClass() []string {
+	return r.ObjectClass
+}
+
+func (r *OrganizationalRole) GetDN() string {
+	return r.DN
+}
+
+func (r *OrganizationalRole) GetPath() string {
+	return r.Path
+}
+
+func (r *OrganizationalRole) GetRelativeDN() string {
+	return "cn=" + r.CommonName
+}
+
+func (r *OrganizationalRole) GetNamingAttribute() (string, string) {
+	return "cn", r.CommonName
+}
+
+func (r *OrganizationalRole) SetDN(dn string) {
+	r.DN = dn
+}
@@ -9,6 +9,7 @@ type OrganizationalUnit struct {
 	Country            string
 	Description        string
 	DN                 string
+	ExtraAttributes    Attributes
 	Name               string
 	ObjectClass        []string
 	OrganizationalUnit string
@@ -19,7 +20,7 @@ type OrganizationalUnit struct {
 }
 
 func (ou *OrganizationalUnit) GetAttributes() Attributes {
-	return Attributes{map[string][]string{
+	attributes := Attributes{map[string][]string{
 		"l":             {ou.City},
 		"c":             {ou.Country},
 		"description":   {ou.Description},
@@ -30,6 +31,8 @@ func (ou *OrganizationalUnit) GetAttributes() Attributes {
 		"st":            {ou.State},
 		"streetAddress": {ou.StreetAddress},
 	}}
+	attributes.Merge(ou.ExtraAttributes)
+	return attributes
 }
 
 func (ou *OrganizationalUnit) SetAttributes(attributes Attributes) {
@@ -37,6 +40,7 @@ func (ou *OrganizationalUnit) SetAttributes(attributes Attributes) {
 	ou.City = attributes.GetFirst("l")
 	ou.Country = attributes.GetFirst("c")
 	ou.Description = attributes.GetFirst("description")
+	ou.ExtraAttributes = attributes.Select(ou.ExtraAttributes.Keys())
 	ou.Name = attributes.GetFirst("name")
 	ou.OrganizationalUnit = attributes.GetFirst("ou")
 	ou.PostalCode = attributes.GetFirst("postalCode")
@@ -60,6 +64,10 @@ func (ou *OrganizationalUnit) GetRelativeDN() string {
 	return "ou=" + ou.OrganizationalUnit
 }
 
+func (ou *OrganizationalUnit) GetNamingAttribute() (string, string) {
+	return "ou", ou.OrganizationalUnit
+}
+
 func (ou *OrganizationalUnit) SetDN(dn string) {
 	ou.DN = dn
 }
@@ -0,0 +1,92 @@
+package ldap
+
+import (
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"sort"
+	"strings"
+)
+
+// detectBaseDN returns the default naming context of the server, or its only naming context when it
+// does not advertise a default.
+func detectBaseDN(rootDSE Attributes) string {
+	if rootDSE.HasValue("defaultNamingContext") {
+		return rootDSE.GetFirst("defaultNamingContext")
+	}
+	if namingContexts := rootDSE.Get("namingContexts"); len(namingContexts) == 1 {
+		return namingContexts[0]
+	}
+	return ""
+}
+
+// pathCustomizeDiff replaces the resource when its path changes, unless the old and new paths
+// resolve to the same entry, e.g. when a fully-qualified path is rewritten relative to the base DN.
+func pathCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("path") {
+		return nil
+	}
+	client := m.(*Client)
+	oldPath, newPath := d.GetChange("path")
+	if dnEqualFold(client.ResolvePath(oldPath.(string)), client.ResolvePath(newPath.(string))) {
+		return nil
+	}
+	return d.ForceNew("path")
+}
+
+// dnEqualFold reports whether a and b are the same distinguished name, ignoring case.
+func dnEqualFold(a string, b string) bool {
+	dnA, err := ldap.ParseDN(a)
+	if err != nil {
+		return strings.EqualFold(a, b)
+	}
+	dnB, err := ldap.ParseDN(b)
+	if err != nil {
+		return false
+	}
+	return len(dnA.RDNs) == len(dnB.RDNs) && dnEndsWith(dnA, dnB)
+}
+
+// dnKey normalizes dn for use as a map key, ignoring case and insignificant whitespace as dnEqualFold does.
+func dnKey(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(dn)
+	}
+	rdns := make([]string, len(parsed.RDNs))
+	for i, rdn := range parsed.RDNs {
+		values := make([]string, len(rdn.Attributes))
+		for j, attr := range rdn.Attributes {
+			values[j] = strings.ToLower(attr.Type + "=" + attr.Value)
+		}
+		sort.Strings(values)
+		rdns[i] = strings.Join(values, "+")
+	}
+	return strings.Join(rdns, ",")
+}
+
+// dnEndsWith reports whether the trailing RDNs of dn match suffix, ignoring case.
+func dnEndsWith(dn *ldap.DN, suffix *ldap.DN) bool {
+	if len(suffix.RDNs) > len(dn.RDNs) {
+		return false
+	}
+	offset := len(dn.RDNs) - len(suffix.RDNs)
+	for i, rdn := range suffix.RDNs {
+		other := dn.RDNs[offset+i]
+		if len(rdn.Attributes) != len(other.Attributes) {
+			return false
+		}
+		for _, attr := range rdn.Attributes {
+			found := false
+			for _, otherAttr := range other.Attributes {
+				if strings.EqualFold(attr.Type, otherAttr.Type) && strings.EqualFold(attr.Value, otherAttr.Value) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -1,9 +1,12 @@
 package ldap
 
 import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"net"
 	url2 "net/url"
+	"time"
 )
 
 func Provider() *schema.Provider {
@@ -23,13 +26,84 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeString,
 				Required:    true,
 				Description: "",
-				Sensitive: true,
+				Sensitive:   true,
+			},
+			"base_dn": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "The distinguished name that relative resource paths are resolved against. Defaults to the default naming context of the server.",
+				ValidateFunc: internal.DistinguishedName(),
+			},
+			"connect_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "30s",
+				Description:  "How long to wait for a connection to the server, as a duration such as \"30s\".",
+				ValidateFunc: internal.Duration(),
+			},
+			"consistency_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "30s",
+				Description:  "How long a read following a write waits for the write to replicate when it is served by another server, as a duration such as \"30s\".",
+				ValidateFunc: internal.Duration(),
+			},
+			"flavor": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      FLAVOR_AUTO,
+				Description:  "The directory server implementation, which determines resource defaults. Detected from the root DSE when \"auto\".",
+				ValidateFunc: validation.StringInSlice([]string{FLAVOR_AUTO, FLAVOR_ACTIVE_DIRECTORY, FLAVOR_OPENLDAP, FLAVOR_389DS}, false),
+			},
+			"global_catalog": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The URL of the Global Catalog searched for service principal names registered anywhere in the forest. Defaults to server on port 3268, or 3269 for ldaps.",
+			},
+			"ldif_output_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A file to which an LDIF change record is appended for every change written to the directory.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				Description:  "How many times a read, or a connection or bind before a write, that fails because the server is busy, unavailable or unreachable is retried, with exponential backoff.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"read_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Fail any operation that would write to the directory, so that plans and refreshes can run with read-only credentials.",
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "60s",
+				Description:  "How long to wait for the server to respond to a request, as a duration such as \"60s\".",
+				ValidateFunc: internal.Duration(),
+			},
+			"validate_schema": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Validate object classes and attributes against the server's subschema during plan.",
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"ldap_organizational_unit": resourceLdapOrganizationalUnit(),
-			"ldap_user":                resourceLdapUser(),
-			"ldap_group":               resourceLdapGroup(),
+			"ldap_computer":               resourceLdapComputer(),
+			"ldap_container":              resourceLdapContainer(),
+			"ldap_domain_component":       resourceLdapDomainComponent(),
+			"ldap_ldif":                   resourceLdapLdif(),
+			"ldap_organization":           resourceLdapOrganization(),
+			"ldap_organizational_role":    resourceLdapOrganizationalRole(),
+			"ldap_organizational_unit":    resourceLdapOrganizationalUnit(),
+			"ldap_service_principal_name": resourceLdapServicePrincipalName(),
+			"ldap_sudo_role":              resourceLdapSudoRole(),
+			"ldap_user":                   resourceLdapUser(),
+			"ldap_group":                  resourceLdapGroup(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -40,17 +114,42 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	if ip := net.ParseIP(url.Host); ip == nil {
-		ips, err := net.LookupIP(url.Host)
+	if ip := net.ParseIP(url.Hostname()); ip == nil {
+		ips, err := net.LookupIP(url.Hostname())
 		if err != nil {
 			return nil, err
 		}
 		url.Host = ips[0].String()
+		if port := url.Port(); port != "" {
+			url.Host = net.JoinHostPort(url.Host, port)
+		}
+	}
+	connectTimeout, err := time.ParseDuration(d.Get("connect_timeout").(string))
+	if err != nil {
+		return nil, err
+	}
+	consistencyTimeout, err := time.ParseDuration(d.Get("consistency_timeout").(string))
+	if err != nil {
+		return nil, err
+	}
+	requestTimeout, err := time.ParseDuration(d.Get("request_timeout").(string))
+	if err != nil {
+		return nil, err
 	}
 	config := Config{
-		Server:       url.String(),
-		BindDN:       d.Get("bind_dn").(string),
-		BindPassword: d.Get("bind_password").(string),
+		Server:             url.String(),
+		BindDN:             d.Get("bind_dn").(string),
+		BindPassword:       d.Get("bind_password").(string),
+		BaseDN:             d.Get("base_dn").(string),
+		ConnectTimeout:     connectTimeout,
+		ConsistencyTimeout: consistencyTimeout,
+		Flavor:             d.Get("flavor").(string),
+		GlobalCatalog:      d.Get("global_catalog").(string),
+		LDIFOutputFile:     d.Get("ldif_output_file").(string),
+		MaxRetries:         d.Get("max_retries").(int),
+		ReadOnly:           d.Get("read_only").(bool),
+		RequestTimeout:     requestTimeout,
+		ValidateSchema:     d.Get("validate_schema").(bool),
 	}
 	return config.Client()
 }
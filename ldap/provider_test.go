@@ -0,0 +1,149 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"strings"
+	"testing"
+)
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testAccProviders returns a new provider for each acceptance test, which configures it for its own server.
+func testAccProviders() map[string]terraform.ResourceProvider {
+	return map[string]terraform.ResourceProvider{"ldap": Provider()}
+}
+
+// testAccServer starts an in-memory server for an acceptance test, which is stopped when the test ends.
+func testAccServer(t *testing.T, config ldaptest.Config) *ldaptest.Server {
+	server, err := ldaptest.NewServer(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server
+}
+
+// testAccClient returns a client of server, for tests that call resource functions directly.
+func testAccClient(t *testing.T, server *ldaptest.Server) *Client {
+	config := Config{Server: server.URL, BindDN: server.Config.BindDN, BindPassword: server.Config.BindPassword}
+	client, err := config.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client.(*Client)
+}
+
+// testAccProviderConfig returns a provider block configured for server, with additional arguments.
+func testAccProviderConfig(server *ldaptest.Server, arguments ...string) string {
+	return fmt.Sprintf(`
+provider "ldap" {
+  server          = %q
+  bind_dn         = %q
+  bind_password   = %q
+  validate_schema = false
+  %s
+}
+`, server.URL, server.Config.BindDN, server.Config.BindPassword, strings.Join(arguments, "\n  "))
+}
+
+// testAccCheckAttribute checks that the entry at dn holds exactly values for the attribute name.
+func testAccCheckAttribute(server *ldaptest.Server, dn string, name string, values ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		attributes := server.Get(dn)
+		if attributes == nil {
+			return fmt.Errorf("entry %q does not exist", dn)
+		}
+		var actual []string
+		for key, v := range attributes {
+			if strings.EqualFold(key, name) {
+				actual = v
+			}
+		}
+		if len(actual) != len(values) {
+			return fmt.Errorf("%s of %q is %q, expected %q", name, dn, actual, values)
+		}
+		for _, value := range values {
+			if !containsFold(actual, value) {
+				return fmt.Errorf("%s of %q is %q, expected %q", name, dn, actual, values)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccCheckDestroyed checks that none of dns exist.
+func testAccCheckDestroyed(server *ldaptest.Server, dns ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		for _, dn := range dns {
+			if server.Get(dn) != nil {
+				return fmt.Errorf("entry %q still exists", dn)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccEntryCase is an acceptance test of a resource that manages the single entry dn. When root is set, the
+// suffix entry of the server is removed first, so that the resource creates the root of the naming context.
+type testAccEntryCase struct {
+	name         string
+	server       ldaptest.Config
+	root         bool
+	resourceName string
+	dn           string
+	steps        []testAccEntryStep
+}
+
+// testAccEntryStep is a configuration of the resource of a testAccEntryCase, with the values it writes to the
+// entry and to the state. An attribute without values must be absent from the entry.
+type testAccEntryStep struct {
+	config     string
+	attributes map[string][]string
+	state      map[string]string
+}
+
+// testAccEntry runs each of cases as a subtest, which applies its steps in turn, imports the resource and
+// checks that destroying it removes the entry.
+func testAccEntry(t *testing.T, cases []testAccEntryCase) {
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			server := testAccServer(t, c.server)
+			if c.root {
+				server.Delete(c.dn)
+			}
+			var steps []resource.TestStep
+			for _, step := range c.steps {
+				checks := []resource.TestCheckFunc{resource.TestCheckResourceAttr(c.resourceName, "id", c.dn)}
+				for name, values := range step.attributes {
+					checks = append(checks, testAccCheckAttribute(server, c.dn, name, values...))
+				}
+				for key, value := range step.state {
+					checks = append(checks, resource.TestCheckResourceAttr(c.resourceName, key, value))
+				}
+				steps = append(steps, resource.TestStep{
+					Config: testAccProviderConfig(server) + step.config,
+					Check:  resource.ComposeTestCheckFunc(checks...),
+				})
+			}
+			steps = append(steps, resource.TestStep{
+				Config:            testAccProviderConfig(server) + c.steps[len(c.steps)-1].config,
+				ResourceName:      c.resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			})
+			resource.Test(t, resource.TestCase{
+				Providers:    testAccProviders(),
+				CheckDestroy: testAccCheckDestroyed(server, c.dn),
+				Steps:        steps,
+			})
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"sort"
+	"strings"
+)
+
+func rdnAttributeSchema(arguments map[string]string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      "cn",
+		Description:  "The attribute that names the object in its distinguished name.",
+		ForceNew:     true,
+		ValidateFunc: validation.StringInSlice(rdnAttributes(arguments), false),
+	}
+}
+
+// rdnAttributeStateUpgrader fills rdn_attribute, added in version 1 of the schema, from the naming attribute of
+// the DN in state. Otherwise its default would plan to replace entries named by another attribute, e.g. "uid=",
+// whenever the plan is made without refreshing.
+func rdnAttributeStateUpgrader(resource *schema.Resource, arguments map[string]string) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: 0,
+		Type:    resource.CoreConfigSchema().ImpliedType(),
+		Upgrade: func(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+			if attribute, _ := rawState["rdn_attribute"].(string); attribute != "" {
+				return rawState, nil
+			}
+			if id, _ := rawState["id"].(string); id != "" {
+				if attribute := relativeDNAttribute(id, arguments); attribute != "" {
+					rawState["rdn_attribute"] = attribute
+				}
+			}
+			return rawState, nil
+		},
+	}
+}
+
+// relativeDNCustomizeDiff requires the argument holding the value of rdn_attribute, as named by
+// arguments, and replaces the resource when that value changes since it names the entry.
+func relativeDNCustomizeDiff(arguments map[string]string) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, m interface{}) error {
+		attribute := d.Get("rdn_attribute").(string)
+		argument, ok := arguments[attribute]
+		if !ok {
+			return nil
+		}
+		if _, ok := d.GetOk(argument); !ok && d.NewValueKnown(argument) {
+			return fmt.Errorf("%s must be set when rdn_attribute is %q", argument, attribute)
+		}
+		if d.Id() != "" && d.HasChange(argument) {
+			return d.ForceNew(argument)
+		}
+		return nil
+	}
+}
+
+// parseRelativeDN splits rdn into one of attributes, in its canonical case, and its value.
+func parseRelativeDN(rdn string, attributes []string) (attribute string, value string, err error) {
+	if separator := strings.IndexByte(rdn, '='); separator > 0 {
+		for _, a := range attributes {
+			if strings.EqualFold(rdn[:separator], a) {
+				return a, rdn[separator+1:], nil
+			}
+		}
+	}
+	prefixes := make([]string, len(attributes))
+	for i, a := range attributes {
+		prefixes[i] = fmt.Sprintf("%q", a+"=")
+	}
+	err = fmt.Errorf("invalid distinguished name; expected prefix %s", strings.Join(prefixes, " or "))
+	return
+}
+
+// relativeDNAttribute returns the naming attribute of dn, one of those named by arguments, or "" if it
+// has none. It fills rdn_attribute in state written before the argument existed, where it is empty.
+func relativeDNAttribute(dn string, arguments map[string]string) string {
+	rdn, _, err := internal.ParseDN(dn)
+	if err != nil {
+		return ""
+	}
+	attribute, _, err := parseRelativeDN(rdn, rdnAttributes(arguments))
+	if err != nil {
+		return ""
+	}
+	return attribute
+}
+
+func rdnAttributes(arguments map[string]string) []string {
+	attributes := make([]string, 0, len(arguments))
+	for attribute := range arguments {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+	return attributes
+}
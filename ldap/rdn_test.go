@@ -0,0 +1,40 @@
+package ldap
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"testing"
+)
+
+func TestRdnAttributeStateUpgrader(t *testing.T) {
+	cases := []struct {
+		resource *schema.Resource
+		id       string
+		expected string
+	}{
+		{resourceLdapUser(), "cn=Alice Smith,ou=People,dc=example,dc=com", "cn"},
+		{resourceLdapUser(), "UID=alice,ou=People,dc=example,dc=com", "uid"},
+		{resourceLdapUser(), "sAMAccountName=alice,ou=People,dc=example,dc=com", "sAMAccountName"},
+		{resourceLdapGroup(), "cn=admins,ou=Groups,dc=example,dc=com", "cn"},
+		{resourceLdapGroup(), "description=admins,ou=Groups,dc=example,dc=com", ""},
+	}
+	for _, c := range cases {
+		if c.resource.SchemaVersion != 1 || len(c.resource.StateUpgraders) != 1 {
+			t.Fatalf("schema version = %d, expected a state upgrader to version 1", c.resource.SchemaVersion)
+		}
+		state, err := c.resource.StateUpgraders[0].Upgrade(map[string]interface{}{"id": c.id}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual, _ := state["rdn_attribute"].(string); actual != c.expected {
+			t.Errorf("rdn_attribute of %q = %q, expected %q", c.id, actual, c.expected)
+		}
+	}
+	// A value already in state is kept
+	state, err := resourceLdapUser().StateUpgraders[0].Upgrade(map[string]interface{}{"id": "cn=alice,dc=example,dc=com", "rdn_attribute": "uid"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := state["rdn_attribute"]; actual != "uid" {
+		t.Errorf("rdn_attribute = %q, expected \"uid\" to be kept", actual)
+	}
+}
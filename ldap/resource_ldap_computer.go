@@ -0,0 +1,285 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"regexp"
+	"strings"
+)
+
+func resourceLdapComputer() *schema.Resource {
+	resource := &schema.Resource{
+		Create: resourceLdapComputerCreate,
+		Read:   resourceLdapComputerRead,
+		Update: resourceLdapComputerUpdate,
+		Delete: resourceLdapComputerDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"attributes": extraAttributesSchema(),
+			"cn": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name that represents the object. Used to perform searches",
+				ForceNew:    true,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a description of the object.",
+			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The display name for an object.",
+			},
+			"dns_host_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the fully qualified domain name (FQDN) of the computer.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Specifies if the computer account is enabled.",
+			},
+			"ignore_attribute_changes": ignoreAttributeChangesSchema(),
+			"location": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the location of the computer, such as an office number.",
+			},
+			"managed_attributes": managedAttributesSchema(),
+			"managed_by": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Specifies the distinguished name of the user or group that manages the computer.",
+				ValidateFunc: internal.DistinguishedName(),
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Specifies the name of the object.",
+				ForceNew:    true,
+			},
+			"object_class": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The list of classes from which this object is derived.",
+			},
+			"operating_system": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies an operating system name.",
+			},
+			"operating_system_version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies an operating system version.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the X.500 path of the OU or container where the new object is created, relative to the provider's base DN unless fully qualified.",
+			},
+			"sam_account_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Specifies the Security Account Manager (SAM) account name of the computer. Defaults to the upper-case common name followed by \"$\".",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[^$]{1,15}\$$`), "must be a NetBIOS name of at most 15 characters followed by \"$\""),
+			},
+			"service_principal_names": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Specifies the service principal names (SPN) of the computer account.",
+			},
+			"user_account_control": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The userAccountControl flags of the computer account, of which only ACCOUNTDISABLE is managed.",
+			},
+		},
+	}
+	resource.CustomizeDiff = customdiff.All(
+		pathCustomizeDiff,
+		extraAttributesCustomizeDiff(&Computer{}),
+		resourceLdapComputerNetBIOSNameCustomizeDiff,
+		subschemaCustomizeDiff(resource.Schema, func(d resourceData, client *Client) (Object, error) {
+			_, obj, err := resourceLdapComputerUnmarshal(d)
+			return obj, err
+		}),
+	)
+	return resource
+}
+
+func resourceLdapComputerCreate(d *schema.ResourceData, m interface{}) error {
+	_, c, err := resourceLdapComputerUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	if err := client.Add(c); err != nil {
+		return err
+	}
+	return resourceLdapComputerRead(d, client)
+}
+
+func resourceLdapComputerRead(d *schema.ResourceData, m interface{}) error {
+	_, c, err := resourceLdapComputerUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client)
+	if err := client.Search(newAttributeFilter(c, d)); err != nil {
+		return err
+	}
+	return resourceLdapComputerMarshal(c, d)
+}
+
+func resourceLdapComputerUpdate(d *schema.ResourceData, m interface{}) error {
+	oldComputer, newComputer, err := resourceLdapComputerUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
+	if err := client.Modify(oldComputer, newComputer); err != nil {
+		return err
+	}
+	return resourceLdapComputerRead(d, client)
+}
+
+func resourceLdapComputerDelete(d *schema.ResourceData, m interface{}) error {
+	_, c, err := resourceLdapComputerUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	if err := client.Delete(c); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceLdapComputerMarshal(c *Computer, d *schema.ResourceData) error {
+	if d.Id() != c.DN {
+		d.SetId(c.DN)
+	}
+	d.Set("attributes", flattenExtraAttributes(c.ExtraAttributes))
+	d.Set("cn", c.CommonName)
+	d.Set("description", c.Description)
+	d.Set("display_name", c.DisplayName)
+	d.Set("dns_host_name", c.DNSHostName)
+	d.Set("enabled", c.Enabled)
+	d.Set("location", c.Location)
+	d.Set("managed_by", c.ManagedBy)
+	d.Set("name", c.Name)
+	d.Set("object_class", c.ObjectClass)
+	d.Set("operating_system", c.OperatingSystem)
+	d.Set("operating_system_version", c.OperatingSystemVersion)
+	d.Set("path", c.Path)
+	d.Set("sam_account_name", c.SamAccountName)
+	d.Set("service_principal_names", c.ServicePrincipalNames)
+	d.Set("user_account_control", c.UserAccountControl)
+	return nil
+}
+
+func resourceLdapComputerUnmarshal(d resourceData) (oldComputer *Computer, newComputer *Computer, err error) {
+	newComputer = &Computer{DN: d.Id(), Enabled: true}
+	oldComputer = &Computer{DN: d.Id(), Enabled: true}
+	if _, ok := d.GetOk("path"); !ok { // Absent on import
+		rdn, path, err := internal.ParseDN(d.Id())
+		if err != nil {
+			return oldComputer, newComputer, err
+		}
+		if !strings.HasPrefix(strings.ToLower(rdn), "cn=") {
+			return nil, nil, errors.New("invalid distinguished name; expected prefix \"cn=\"")
+		}
+		newComputer.CommonName = rdn[3:]
+		newComputer.Path = path
+	} else {
+		properties := map[string]func(*Computer, interface{}){
+			"attributes":    func(c *Computer, v interface{}) { c.ExtraAttributes = expandExtraAttributes(v) },
+			"cn":            func(c *Computer, v interface{}) { c.CommonName = v.(string) },
+			"description":   func(c *Computer, v interface{}) { c.Description = v.(string) },
+			"display_name":  func(c *Computer, v interface{}) { c.DisplayName = v.(string) },
+			"dns_host_name": func(c *Computer, v interface{}) { c.DNSHostName = v.(string) },
+			"enabled":       func(c *Computer, v interface{}) { c.Enabled = v.(bool) },
+			"location":      func(c *Computer, v interface{}) { c.Location = v.(string) },
+			"managed_by":    func(c *Computer, v interface{}) { c.ManagedBy = v.(string) },
+			"name":          func(c *Computer, v interface{}) { c.Name = v.(string) },
+			"object_class": func(c *Computer, v interface{}) {
+				set := v.(*schema.Set)
+				if set.Len() > 0 {
+					objectClass := make([]string, 0)
+					for _, oc := range set.List() {
+						objectClass = append(objectClass, oc.(string))
+					}
+					c.ObjectClass = objectClass
+				} else {
+					c.ObjectClass = []string{top, PERSON, ORGANIZATIONAL_PERSON, USER, COMPUTER}
+					for _, objectClass := range c.ObjectClass {
+						set.Add(objectClass)
+					}
+				}
+			},
+			"operating_system":         func(c *Computer, v interface{}) { c.OperatingSystem = v.(string) },
+			"operating_system_version": func(c *Computer, v interface{}) { c.OperatingSystemVersion = v.(string) },
+			"path":                     func(c *Computer, v interface{}) { c.Path = v.(string) },
+			"sam_account_name":         func(c *Computer, v interface{}) { c.SamAccountName = v.(string) },
+			"service_principal_names": func(c *Computer, v interface{}) {
+				set := v.(*schema.Set)
+				servicePrincipalNames := make([]string, 0)
+				for _, spn := range set.List() {
+					servicePrincipalNames = append(servicePrincipalNames, spn.(string))
+				}
+				c.ServicePrincipalNames = servicePrincipalNames
+			},
+			"user_account_control": func(c *Computer, v interface{}) { c.UserAccountControl = v.(int) },
+		}
+		for property, fn := range properties {
+			newVal := d.Get(property)
+			fn(newComputer, newVal)
+			if d.HasChange(property) {
+				oldVal, _ := d.GetChange(property)
+				fn(oldComputer, oldVal)
+			} else {
+				fn(oldComputer, newVal)
+			}
+		}
+	}
+	return
+}
+
+// resourceLdapComputerNetBIOSNameCustomizeDiff rejects a cn too long for the NetBIOS name derived from it when
+// sam_account_name is not set, which would otherwise only fail when the computer is created.
+func resourceLdapComputerNetBIOSNameCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" || !d.NewValueKnown("cn") {
+		return nil
+	}
+	c := &Computer{CommonName: d.Get("cn").(string), SamAccountName: d.Get("sam_account_name").(string)}
+	if name := strings.TrimSuffix(c.samAccountName(), "$"); len(name) > NETBIOS_NAME_MAX_LENGTH {
+		return fmt.Errorf("the NetBIOS name %q is longer than %d characters; shorten cn or set sam_account_name", name, NETBIOS_NAME_MAX_LENGTH)
+	}
+	return nil
+}
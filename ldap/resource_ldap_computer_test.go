@@ -0,0 +1,86 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAccLdapComputer_basic(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{ActiveDirectory: true})
+	dn := "cn=WEB01,dc=example,dc=com"
+	config := func(enabled bool) string {
+		return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "ldap_computer" "web01" {
+  cn            = "WEB01"
+  path          = "dc=example,dc=com"
+  dns_host_name = "web01.example.com"
+  enabled       = %t
+}
+`, enabled)
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_computer.web01", "sam_account_name", "WEB01$"),
+					testAccCheckAttribute(server, dn, "objectClass", "top", "person", "organizationalPerson", "user", "computer"),
+					testAccCheckAttribute(server, dn, "userAccountControl", "4128"),
+				),
+			},
+			{
+				// Joining the domain sets a password and the computer may be trusted for delegation
+				PreConfig: func() {
+					attributes := server.Get(dn)
+					attributes["userAccountControl"] = []string{fmt.Sprintf("%d", WORKSTATION_TRUST_ACCOUNT|0x80000)}
+					server.Put(dn, attributes)
+				},
+				Config: config(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_computer.web01", "enabled", "false"),
+					testAccCheckAttribute(server, dn, "userAccountControl", fmt.Sprintf("%d", WORKSTATION_TRUST_ACCOUNT|0x80000|ACCOUNTDISABLE)),
+				),
+			},
+			{
+				Config:            config(false),
+				ResourceName:      "ldap_computer.web01",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccLdapComputer_netBIOSName(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{ActiveDirectory: true})
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_computer" "web" {
+  cn               = "WEB-FRONTEND-0001"
+  path             = "dc=example,dc=com"
+  sam_account_name = "WEB-FRONTEND-0001$"
+}
+`,
+				ExpectError: regexp.MustCompile(`must be a NetBIOS name of at most 15 characters`),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_computer" "web" {
+  cn   = "WEB-FRONTEND-0001"
+  path = "dc=example,dc=com"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`NetBIOS name "WEB-FRONTEND-0001" is longer than 15 characters`),
+			},
+		},
+	})
+}
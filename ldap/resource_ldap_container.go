@@ -0,0 +1,184 @@
+package ldap
+
+import (
+	"errors"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"strings"
+)
+
+func resourceLdapContainer() *schema.Resource {
+	resource := &schema.Resource{
+		Create: resourceLdapContainerCreate,
+		Read:   resourceLdapContainerRead,
+		Update: resourceLdapContainerUpdate,
+		Delete: resourceLdapContainerDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"attributes": extraAttributesSchema(),
+			"cn": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name that represents the object. Used to perform searches",
+				ForceNew:    true,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a description of the object.",
+			},
+			"ignore_attribute_changes": ignoreAttributeChangesSchema(),
+			"managed_attributes":       managedAttributesSchema(),
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Specifies the name of the object.",
+				ForceNew:    true,
+			},
+			"object_class": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The list of classes from which this object is derived.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the X.500 path of the OU or container where the new object is created, relative to the provider's base DN unless fully qualified.",
+			},
+		},
+	}
+	resource.CustomizeDiff = customdiff.All(
+		pathCustomizeDiff,
+		extraAttributesCustomizeDiff(&Container{}),
+		subschemaCustomizeDiff(resource.Schema, func(d resourceData, client *Client) (Object, error) {
+			_, obj, err := resourceLdapContainerUnmarshal(d)
+			return obj, err
+		}),
+	)
+	return resource
+}
+
+func resourceLdapContainerCreate(d *schema.ResourceData, m interface{}) error {
+	_, c, err := resourceLdapContainerUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	if err := client.Add(c); err != nil {
+		return err
+	}
+	return resourceLdapContainerRead(d, client)
+}
+
+func resourceLdapContainerRead(d *schema.ResourceData, m interface{}) error {
+	_, c, err := resourceLdapContainerUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client)
+	if err := client.Search(newAttributeFilter(c, d)); err != nil {
+		return err
+	}
+	return resourceLdapContainerMarshal(c, d)
+}
+
+func resourceLdapContainerUpdate(d *schema.ResourceData, m interface{}) error {
+	oldContainer, newContainer, err := resourceLdapContainerUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
+	if err := client.Modify(oldContainer, newContainer); err != nil {
+		return err
+	}
+	return resourceLdapContainerRead(d, client)
+}
+
+func resourceLdapContainerDelete(d *schema.ResourceData, m interface{}) error {
+	_, c, err := resourceLdapContainerUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	if err := client.Delete(c); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceLdapContainerMarshal(c *Container, d *schema.ResourceData) error {
+	if d.Id() != c.DN {
+		d.SetId(c.DN)
+	}
+	d.Set("attributes", flattenExtraAttributes(c.ExtraAttributes))
+	d.Set("cn", c.CommonName)
+	d.Set("description", c.Description)
+	d.Set("name", c.Name)
+	d.Set("object_class", c.ObjectClass)
+	d.Set("path", c.Path)
+	return nil
+}
+
+func resourceLdapContainerUnmarshal(d resourceData) (oldContainer *Container, newContainer *Container, err error) {
+	newContainer = &Container{DN: d.Id()}
+	oldContainer = &Container{DN: d.Id()}
+	if _, ok := d.GetOk("path"); !ok { // Absent on import
+		rdn, path, err := internal.ParseDN(d.Id())
+		if err != nil {
+			return oldContainer, newContainer, err
+		}
+		if !strings.HasPrefix(strings.ToLower(rdn), "cn=") {
+			return nil, nil, errors.New("invalid distinguished name; expected prefix \"cn=\"")
+		}
+		newContainer.CommonName = rdn[3:]
+		newContainer.Path = path
+	} else {
+		properties := map[string]func(*Container, interface{}){
+			"attributes":  func(c *Container, v interface{}) { c.ExtraAttributes = expandExtraAttributes(v) },
+			"cn":          func(c *Container, v interface{}) { c.CommonName = v.(string) },
+			"description": func(c *Container, v interface{}) { c.Description = v.(string) },
+			"name":        func(c *Container, v interface{}) { c.Name = v.(string) },
+			"object_class": func(c *Container, v interface{}) {
+				set := v.(*schema.Set)
+				if set.Len() > 0 {
+					objectClass := make([]string, 0)
+					for _, c := range set.List() {
+						objectClass = append(objectClass, c.(string))
+					}
+					c.ObjectClass = objectClass
+				} else {
+					c.ObjectClass = []string{top, CONTAINER}
+					for _, objectClass := range c.ObjectClass {
+						set.Add(objectClass)
+					}
+				}
+			},
+			"path": func(c *Container, v interface{}) { c.Path = v.(string) },
+		}
+		for property, fn := range properties {
+			newVal := d.Get(property)
+			fn(newContainer, newVal)
+			if d.HasChange(property) {
+				oldVal, _ := d.GetChange(property)
+				fn(oldContainer, oldVal)
+			} else {
+				fn(oldContainer, newVal)
+			}
+		}
+	}
+	return
+}
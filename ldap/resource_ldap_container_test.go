@@ -0,0 +1,69 @@
+package ldap
+
+import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"testing"
+)
+
+func TestAccLdapContainer_basic(t *testing.T) {
+	testAccEntry(t, []testAccEntryCase{
+		{
+			name:         "defaults",
+			server:       ldaptest.Config{ActiveDirectory: true},
+			resourceName: "ldap_container.service_accounts",
+			dn:           "cn=Service Accounts,dc=example,dc=com",
+			steps: []testAccEntryStep{
+				{
+					config: `
+resource "ldap_container" "service_accounts" {
+  cn          = "Service Accounts"
+  path        = "dc=example,dc=com"
+  description = "Accounts used by applications"
+}
+`,
+					attributes: map[string][]string{
+						"objectClass": {"top", "container"},
+						"description": {"Accounts used by applications"},
+					},
+				},
+				{
+					config: `
+resource "ldap_container" "service_accounts" {
+  cn          = "Service Accounts"
+  path        = "dc=example,dc=com"
+  description = "Application accounts"
+}
+`,
+					attributes: map[string][]string{"description": {"Application accounts"}},
+				},
+				{
+					config: `
+resource "ldap_container" "service_accounts" {
+  cn   = "Service Accounts"
+  path = "dc=example,dc=com"
+}
+`,
+					attributes: map[string][]string{"description": nil},
+				},
+			},
+		},
+		{
+			name:         "objectClass",
+			server:       ldaptest.Config{ActiveDirectory: true},
+			resourceName: "ldap_container.service_accounts",
+			dn:           "cn=Service Accounts,dc=example,dc=com",
+			steps: []testAccEntryStep{
+				{
+					config: `
+resource "ldap_container" "service_accounts" {
+  cn           = "Service Accounts"
+  path         = "dc=example,dc=com"
+  object_class = ["top", "container", "extensibleObject"]
+}
+`,
+					attributes: map[string][]string{"objectClass": {"top", "container", "extensibleObject"}},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,179 @@
+package ldap
+
+import (
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"strings"
+)
+
+func resourceLdapDomainComponent() *schema.Resource {
+	resource := &schema.Resource{
+		Create: resourceLdapDomainComponentCreate,
+		Read:   resourceLdapDomainComponentRead,
+		Update: resourceLdapDomainComponentUpdate,
+		Delete: resourceLdapDomainComponentDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"attributes": extraAttributesSchema(),
+			"dc": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The domain component name",
+				ForceNew:    true,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a description of the object.",
+			},
+			"ignore_attribute_changes": ignoreAttributeChangesSchema(),
+			"managed_attributes":       managedAttributesSchema(),
+			"object_class": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The list of classes from which this object is derived.",
+			},
+			"organization": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Specifies the organization name. Defaults to the domain component when the object is an organization.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the X.500 path of the parent of the new object, relative to the provider's base DN unless fully qualified. Omit it for a domain component at the root of a naming context, e.g. \"dc=com\".",
+			},
+		},
+	}
+	resource.CustomizeDiff = customdiff.All(
+		pathCustomizeDiff,
+		extraAttributesCustomizeDiff(&DomainComponent{}),
+		subschemaCustomizeDiff(resource.Schema, func(d resourceData, client *Client) (Object, error) {
+			_, obj, err := resourceLdapDomainComponentUnmarshal(d)
+			return obj, err
+		}),
+	)
+	return resource
+}
+
+func resourceLdapDomainComponentCreate(d *schema.ResourceData, m interface{}) error {
+	_, dc, err := resourceLdapDomainComponentUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	if err := client.Add(dc); err != nil {
+		return err
+	}
+	return resourceLdapDomainComponentRead(d, client)
+}
+
+func resourceLdapDomainComponentRead(d *schema.ResourceData, m interface{}) error {
+	_, dc, err := resourceLdapDomainComponentUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client)
+	if err := client.Search(newAttributeFilter(dc, d)); err != nil {
+		return err
+	}
+	return resourceLdapDomainComponentMarshal(dc, d)
+}
+
+func resourceLdapDomainComponentUpdate(d *schema.ResourceData, m interface{}) error {
+	oldDomainComponent, newDomainComponent, err := resourceLdapDomainComponentUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
+	if err := client.Modify(oldDomainComponent, newDomainComponent); err != nil {
+		return err
+	}
+	return resourceLdapDomainComponentRead(d, client)
+}
+
+func resourceLdapDomainComponentDelete(d *schema.ResourceData, m interface{}) error {
+	_, dc, err := resourceLdapDomainComponentUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	if err := client.Delete(dc); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceLdapDomainComponentMarshal(dc *DomainComponent, d *schema.ResourceData) error {
+	if d.Id() != dc.DN {
+		d.SetId(dc.DN)
+	}
+	d.Set("attributes", flattenExtraAttributes(dc.ExtraAttributes))
+	d.Set("dc", dc.DomainComponent)
+	d.Set("description", dc.Description)
+	d.Set("object_class", dc.ObjectClass)
+	d.Set("organization", dc.Organization)
+	d.Set("path", dc.Path)
+	return nil
+}
+
+func resourceLdapDomainComponentUnmarshal(d resourceData) (oldDomainComponent *DomainComponent, newDomainComponent *DomainComponent, err error) {
+	newDomainComponent = &DomainComponent{DN: d.Id()}
+	oldDomainComponent = &DomainComponent{DN: d.Id()}
+	if _, ok := d.GetOk("dc"); !ok { // Absent on import
+		rdn, path := splitDN(d.Id())
+		if !strings.HasPrefix(strings.ToLower(rdn), "dc=") {
+			return nil, nil, errors.New("invalid distinguished name; expected prefix \"dc=\"")
+		}
+		newDomainComponent.DomainComponent = rdn[3:]
+		newDomainComponent.Path = path
+	} else {
+		properties := map[string]func(*DomainComponent, interface{}){
+			"attributes":  func(dc *DomainComponent, v interface{}) { dc.ExtraAttributes = expandExtraAttributes(v) },
+			"dc":          func(dc *DomainComponent, v interface{}) { dc.DomainComponent = v.(string) },
+			"description": func(dc *DomainComponent, v interface{}) { dc.Description = v.(string) },
+			"object_class": func(dc *DomainComponent, v interface{}) {
+				set := v.(*schema.Set)
+				if set.Len() > 0 {
+					objectClass := make([]string, 0)
+					for _, c := range set.List() {
+						objectClass = append(objectClass, c.(string))
+					}
+					dc.ObjectClass = objectClass
+				} else {
+					dc.ObjectClass = []string{top, DC_OBJECT, ORGANIZATION}
+					for _, objectClass := range dc.ObjectClass {
+						set.Add(objectClass)
+					}
+				}
+			},
+			"organization": func(dc *DomainComponent, v interface{}) { dc.Organization = v.(string) },
+			"path":         func(dc *DomainComponent, v interface{}) { dc.Path = v.(string) },
+		}
+		for property, fn := range properties {
+			newVal := d.Get(property)
+			fn(newDomainComponent, newVal)
+			if d.HasChange(property) {
+				oldVal, _ := d.GetChange(property)
+				fn(oldDomainComponent, oldVal)
+			} else {
+				fn(oldDomainComponent, newVal)
+			}
+		}
+	}
+	return
+}
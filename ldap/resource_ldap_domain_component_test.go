@@ -0,0 +1,83 @@
+package ldap
+
+import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"testing"
+)
+
+func TestAccLdapDomainComponent_basic(t *testing.T) {
+	testAccEntry(t, []testAccEntryCase{
+		{
+			name:         "defaults",
+			server:       ldaptest.Config{Suffix: "dc=com"},
+			resourceName: "ldap_domain_component.example",
+			dn:           "dc=example,dc=com",
+			steps: []testAccEntryStep{
+				{
+					// The organization defaults to the domain component
+					config: `
+resource "ldap_domain_component" "example" {
+  dc   = "example"
+  path = "dc=com"
+}
+`,
+					attributes: map[string][]string{
+						"objectClass": {"top", "dcObject", "organization"},
+						"o":           {"example"},
+					},
+				},
+				{
+					config: `
+resource "ldap_domain_component" "example" {
+  dc           = "example"
+  path         = "dc=com"
+  organization = "Example Corp"
+}
+`,
+					attributes: map[string][]string{"o": {"Example Corp"}},
+				},
+			},
+		},
+		{
+			// The root entry of a naming context has no parent
+			name:         "root",
+			server:       ldaptest.Config{Suffix: "dc=com"},
+			root:         true,
+			resourceName: "ldap_domain_component.com",
+			dn:           "dc=com",
+			steps: []testAccEntryStep{
+				{
+					config: `
+resource "ldap_domain_component" "com" {
+  dc = "com"
+}
+`,
+					attributes: map[string][]string{
+						"objectClass": {"top", "dcObject", "organization"},
+						"dc":          {"com"},
+						"o":           {"com"},
+					},
+					state: map[string]string{"path": ""},
+				},
+			},
+		},
+		{
+			name:         "objectClass",
+			server:       ldaptest.Config{Suffix: "dc=com"},
+			resourceName: "ldap_domain_component.example",
+			dn:           "dc=example,dc=com",
+			steps: []testAccEntryStep{
+				{
+					config: `
+resource "ldap_domain_component" "example" {
+  dc           = "example"
+  path         = "dc=com"
+  object_class = ["top", "dcObject", "organization", "extensibleObject"]
+}
+`,
+					attributes: map[string][]string{"objectClass": {"top", "dcObject", "organization", "extensibleObject"}},
+				},
+			},
+		},
+	})
+}
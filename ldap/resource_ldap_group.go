@@ -1,24 +1,35 @@
 package ldap
 
 import (
-	"errors"
 	"fmt"
 	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
-	"strings"
 )
 
+// groupRDNArguments maps the attributes that may name a group to the arguments holding their values.
+var groupRDNArguments = map[string]string{
+	"cn":             "cn",
+	"sAMAccountName": "sam_account_name",
+}
+
 func resourceLdapGroup() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		Create: resourceLdapGroupCreate,
 		Read:   resourceLdapGroupRead,
 		Update: resourceLdapGroupUpdate,
 		Delete: resourceLdapGroupDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
 		Schema: map[string]*schema.Schema{
+			"attributes": extraAttributesSchema(),
 			"cn": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -59,12 +70,14 @@ func resourceLdapGroup() *schema.Resource {
 				Optional:    true,
 				Description: "Specifies the URL of the home page of the object.",
 			},
+			"ignore_attribute_changes": ignoreAttributeChangesSchema(),
+			"managed_attributes":       managedAttributesSchema(),
 			"members": {
 				Type:          schema.TypeSet,
 				Optional:      true,
 				Elem:          &schema.Schema{Type: schema.TypeString},
 				Set:           schema.HashString,
-				ConflictsWith: []string{"member_uids"},
+				ConflictsWith: []string{"member_uids", "unique_members"},
 				Description:   "Specifies an array of user, group, and computer objects to add to the group.",
 			},
 			"member_uids": {
@@ -93,9 +106,15 @@ func resourceLdapGroup() *schema.Resource {
 			"path": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "Specifies the X.500 path of the OU or container where the new object is created.",
-				ForceNew:    true,
+				Description: "Specifies the X.500 path of the OU or container where the new object is created, relative to the provider's base DN unless fully qualified.",
+			},
+			"placeholder_member": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  fmt.Sprintf("Specifies the member added to an empty \"%s\" or \"%s\" group, which requires at least one member. Defaults to the group itself.", GROUP_OF_NAMES, GROUP_OF_UNIQUE_NAMES),
+				ValidateFunc: internal.DistinguishedName(),
 			},
+			"rdn_attribute": rdnAttributeSchema(groupRDNArguments),
 			"sam_account_name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -108,52 +127,78 @@ func resourceLdapGroup() *schema.Resource {
 				Computed:    true,
 				Description: "Specifies the Security Account Manager (SAM) account type of the group.",
 			},
+			"unique_members": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: internal.DistinguishedName(),
+				},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"members"},
+				Description:   "Specifies the distinguished names of the unique members of a groupOfUniqueNames group.",
+			},
 		},
 	}
+	resource.CustomizeDiff = customdiff.All(
+		pathCustomizeDiff,
+		relativeDNCustomizeDiff(groupRDNArguments),
+		extraAttributesCustomizeDiff(&Group{Flavor: FLAVOR_ACTIVE_DIRECTORY, ObjectClass: []string{GROUP_OF_NAMES, GROUP_OF_UNIQUE_NAMES}}),
+		activeDirectoryCustomizeDiff("group_category", "group_scope", "sam_account_name", "sam_account_type"),
+		subschemaCustomizeDiff(resource.Schema, func(d resourceData, client *Client) (Object, error) {
+			_, obj, err := resourceLdapGroupUnmarshal(d, client)
+			return obj, err
+		}),
+	)
+	resource.SchemaVersion = 1
+	resource.StateUpgraders = []schema.StateUpgrader{rdnAttributeStateUpgrader(resource, groupRDNArguments)}
+	return resource
 }
 
 func resourceLdapGroupCreate(d *schema.ResourceData, m interface{}) error {
-	_, g, err := resourceLdapGroupUnmarshal(d)
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	_, g, err := resourceLdapGroupUnmarshal(d, client)
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
 	if err := client.Add(g); err != nil {
 		return err
 	}
-	return resourceLdapGroupRead(d, m)
+	return resourceLdapGroupRead(d, client)
 }
 
 func resourceLdapGroupRead(d *schema.ResourceData, m interface{}) error {
-	_, g, err := resourceLdapGroupUnmarshal(d)
+	client := m.(*Client)
+	_, g, err := resourceLdapGroupUnmarshal(d, client)
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
-	if err := client.Search(g); err != nil {
+	if err := client.Search(newAttributeFilter(g, d)); err != nil {
 		return err
 	}
 	return resourceLdapGroupMarshal(g, d)
 }
 
 func resourceLdapGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	oldGroup, newGroup, err := resourceLdapGroupUnmarshal(d)
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
+	oldGroup, newGroup, err := resourceLdapGroupUnmarshal(d, client)
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
 	if err := client.Modify(oldGroup, newGroup); err != nil {
 		return err
 	}
-	return resourceLdapGroupRead(d, m)
+	return resourceLdapGroupRead(d, client)
 }
 
 func resourceLdapGroupDelete(d *schema.ResourceData, m interface{}) error {
-	_, g, err := resourceLdapGroupUnmarshal(d)
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	_, g, err := resourceLdapGroupUnmarshal(d, client)
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
 	if err := client.Delete(g); err != nil {
 		return err
 	}
@@ -164,6 +209,7 @@ func resourceLdapGroupMarshal(g *Group, d *schema.ResourceData) error {
 	if d.Id() != g.DN {
 		d.SetId(g.DN)
 	}
+	d.Set("attributes", flattenExtraAttributes(g.ExtraAttributes))
 	d.Set("cn", g.CommonName)
 	d.Set("description", g.Description)
 	d.Set("display_name", g.DisplayName)
@@ -176,26 +222,28 @@ func resourceLdapGroupMarshal(g *Group, d *schema.ResourceData) error {
 	d.Set("name", g.Name)
 	d.Set("object_class", g.ObjectClass)
 	d.Set("path", g.Path)
+	d.Set("rdn_attribute", g.RDNAttribute)
 	d.Set("sam_account_name", g.SamAccountName)
 	d.Set("sam_account_type", g.SamAccountType)
+	d.Set("unique_members", g.UniqueMembers)
 	return nil
 }
 
-func resourceLdapGroupUnmarshal(d *schema.ResourceData) (oldGroup *Group, newGroup *Group, err error) {
-	newGroup = &Group{DN: d.Id()}
-	oldGroup = &Group{DN: d.Id()}
+func resourceLdapGroupUnmarshal(d resourceData, client *Client) (oldGroup *Group, newGroup *Group, err error) {
+	newGroup = &Group{DN: d.Id(), Flavor: client.Flavor}
+	oldGroup = &Group{DN: d.Id(), Flavor: client.Flavor}
 	if _, ok := d.GetOk("path"); !ok { // Absent on import
 		rdn, path, err := internal.ParseDN(d.Id())
 		if err != nil {
 			return oldGroup, newGroup, err
 		}
-		if !strings.HasPrefix(strings.ToLower(rdn), "cn=") {
-			return nil, nil, errors.New("invalid distinguished name; expected prefix \"cn=\"")
+		if err := newGroup.setRelativeDN(rdn); err != nil {
+			return nil, nil, err
 		}
-		newGroup.CommonName = rdn[3:]
 		newGroup.Path = path
 	} else {
 		properties := map[string]func(*Group, interface{}){
+			"attributes":     func(g *Group, v interface{}) { g.ExtraAttributes = expandExtraAttributes(v) },
 			"cn":             func(g *Group, v interface{}) { g.CommonName = v.(string) },
 			"description":    func(g *Group, v interface{}) { g.Description = v.(string) },
 			"display_name":   func(g *Group, v interface{}) { g.DisplayName = v.(string) },
@@ -229,15 +277,25 @@ func resourceLdapGroupUnmarshal(d *schema.ResourceData) (oldGroup *Group, newGro
 					}
 					g.ObjectClass = objectClass
 				} else {
-					g.ObjectClass = []string{top, GROUP}
+					g.ObjectClass = groupDefaultObjectClass(d, client.Flavor)
 					for _, objectClass := range g.ObjectClass {
 						set.Add(objectClass)
 					}
 				}
 			},
-			"path":             func(g *Group, v interface{}) { g.Path = v.(string) },
-			"sam_account_name": func(g *Group, v interface{}) { g.SamAccountName = v.(string) },
-			"sam_account_type": func(g *Group, v interface{}) { g.SamAccountType = v.(string) },
+			"path":               func(g *Group, v interface{}) { g.Path = v.(string) },
+			"placeholder_member": func(g *Group, v interface{}) { g.PlaceholderMember = v.(string) },
+			"rdn_attribute":      func(g *Group, v interface{}) { g.RDNAttribute = v.(string) },
+			"sam_account_name":   func(g *Group, v interface{}) { g.SamAccountName = v.(string) },
+			"sam_account_type":   func(g *Group, v interface{}) { g.SamAccountType = v.(string) },
+			"unique_members": func(g *Group, v interface{}) {
+				set := v.(*schema.Set)
+				uniqueMembers := make([]string, 0)
+				for _, m := range set.List() {
+					uniqueMembers = append(uniqueMembers, m.(string))
+				}
+				g.UniqueMembers = uniqueMembers
+			},
 		}
 		for property, fn := range properties {
 			newVal := d.Get(property)
@@ -249,6 +307,25 @@ func resourceLdapGroupUnmarshal(d *schema.ResourceData) (oldGroup *Group, newGro
 				fn(oldGroup, newVal)
 			}
 		}
+		if newGroup.RDNAttribute == "" && d.Id() != "" { // State written before rdn_attribute existed
+			newGroup.RDNAttribute = relativeDNAttribute(d.Id(), groupRDNArguments)
+			oldGroup.RDNAttribute = newGroup.RDNAttribute
+		}
 	}
 	return
 }
+
+// groupDefaultObjectClass returns the object classes of a group whose object_class is not configured,
+// based on the server flavor and the kind of membership configured.
+func groupDefaultObjectClass(d resourceData, flavor string) []string {
+	if set, ok := d.Get("unique_members").(*schema.Set); ok && set.Len() > 0 {
+		return []string{top, GROUP_OF_UNIQUE_NAMES}
+	}
+	if flavor == FLAVOR_ACTIVE_DIRECTORY {
+		return []string{top, GROUP}
+	}
+	if set, ok := d.Get("member_uids").(*schema.Set); ok && set.Len() > 0 {
+		return []string{top, POSIX_GROUP}
+	}
+	return []string{top, GROUP_OF_NAMES}
+}
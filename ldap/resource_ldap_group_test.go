@@ -0,0 +1,188 @@
+package ldap
+
+import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"testing"
+)
+
+func TestAccLdapGroup_groupOfNames(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "cn=admins,dc=example,dc=com"
+	config := func(members string) string {
+		return testAccProviderConfig(server) + `
+resource "ldap_group" "admins" {
+  cn          = "admins"
+  path        = "dc=example,dc=com"
+  description = "Administrators"
+  members     = [` + members + `]
+}
+`
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_group.admins", "id", dn),
+					resource.TestCheckResourceAttr("ldap_group.admins", "members.#", "0"),
+					testAccCheckAttribute(server, dn, "objectClass", "top", "groupOfNames"),
+					testAccCheckAttribute(server, dn, "member", dn),
+				),
+			},
+			{
+				Config: config(`"uid=alice,dc=example,dc=com", "uid=bob,dc=example,dc=com"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_group.admins", "members.#", "2"),
+					testAccCheckAttribute(server, dn, "member", "uid=alice,dc=example,dc=com", "uid=bob,dc=example,dc=com"),
+				),
+			},
+			{
+				Config: config(`"uid=bob,dc=example,dc=com"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_group.admins", "members.#", "1"),
+					testAccCheckAttribute(server, dn, "member", "uid=bob,dc=example,dc=com"),
+				),
+			},
+			{
+				Config:            config(`"uid=bob,dc=example,dc=com"`),
+				ResourceName:      "ldap_group.admins",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccLdapGroup_posixGroup(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "cn=developers,dc=example,dc=com"
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_group" "developers" {
+  cn          = "developers"
+  path        = "dc=example,dc=com"
+  gid_number  = 5000
+  member_uids = ["alice", "bob"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_group.developers", "gid_number", "5000"),
+					testAccCheckAttribute(server, dn, "objectClass", "top", "posixGroup"),
+					testAccCheckAttribute(server, dn, "gidNumber", "5000"),
+					testAccCheckAttribute(server, dn, "memberUid", "alice", "bob"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapGroup_activeDirectory(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "cn=Sales Managers,dc=example,dc=com"
+	config := func(category string, scope string) string {
+		return testAccProviderConfig(server, `flavor = "active_directory"`) + `
+resource "ldap_group" "sales_managers" {
+  cn               = "Sales Managers"
+  path             = "dc=example,dc=com"
+  sam_account_name = "SalesManagers"
+  group_category   = "` + category + `"
+  group_scope      = "` + scope + `"
+}
+`
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: config("Security", "Global"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_group.sales_managers", "group_category", "Security"),
+					resource.TestCheckResourceAttr("ldap_group.sales_managers", "group_scope", "Global"),
+					testAccCheckAttribute(server, dn, "objectClass", "top", "group"),
+					testAccCheckAttribute(server, dn, "groupType", "-2147483646"),
+					testAccCheckAttribute(server, dn, "sAMAccountName", "SalesManagers"),
+				),
+			},
+			{
+				Config: config("Distribution", "Universal"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_group.sales_managers", "group_category", "Distribution"),
+					resource.TestCheckResourceAttr("ldap_group.sales_managers", "group_scope", "Universal"),
+					testAccCheckAttribute(server, dn, "groupType", "8"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapGroup_activeDirectoryDetected(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{ActiveDirectory: true})
+	dn := "cn=Sales,dc=example,dc=com"
+	config := testAccProviderConfig(server) + `
+resource "ldap_group" "sales" {
+  cn               = "Sales"
+  path             = "dc=example,dc=com"
+  sam_account_name = "Sales"
+  group_category   = "Security"
+  group_scope      = "Global"
+}
+`
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAttribute(server, dn, "objectClass", "top", "group"),
+					testAccCheckAttribute(server, dn, "groupType", "-2147483646"),
+				),
+			},
+			{ // Import must read the Active Directory attributes although the object class is not yet known
+				Config:            config,
+				ResourceName:      "ldap_group.sales",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// State written before rdn_attribute existed must refresh to the naming attribute of the DN, rather than
+// planning to replace the group.
+func TestLdapGroup_upgradeRDNAttribute(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "cn=admins,dc=example,dc=com"
+	server.Put(dn, map[string][]string{"objectClass": {"top", "groupOfNames"}, "cn": {"admins"}, "member": {dn}})
+	client := testAccClient(t, server)
+	r := resourceLdapGroup()
+	d := r.Data(&terraform.InstanceState{ID: dn, Attributes: map[string]string{
+		"id":             dn,
+		"cn":             "admins",
+		"path":           "dc=example,dc=com",
+		"object_class.#": "2",
+	}})
+	if err := resourceLdapGroupRead(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if actual := d.Get("rdn_attribute"); actual != "cn" {
+		t.Fatalf("rdn_attribute = %q, expected \"cn\"", actual)
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"cn": "admins", "path": "dc=example,dc=com"})
+	diff, err := r.Diff(d.State(), config, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.RequiresNew() {
+		t.Errorf("diff replaces the group: %v", diff)
+	}
+}
@@ -0,0 +1,326 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+func resourceLdapLdif() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLdapLdifCreate,
+		Read:   resourceLdapLdifRead,
+		Update: resourceLdapLdifUpdate,
+		Delete: resourceLdapLdifDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
+		Schema: map[string]*schema.Schema{
+			"dns": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The distinguished names of the entries that exist, parents before children.",
+			},
+			"ldif": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				Description:      "An LDIF document of content and change records describing the entries to create and the changes to apply.",
+				DiffSuppressFunc: resourceLdapLdifSuppressEquivalent,
+				ValidateFunc: func(i interface{}, k string) (warnings []string, errors []error) {
+					if _, _, err := resourceLdapLdifRecords(i.(string)); err != nil {
+						errors = append(errors, fmt.Errorf("%s: %v", k, err))
+					}
+					return
+				},
+			},
+		},
+	}
+}
+
+func resourceLdapLdifCreate(d *schema.ResourceData, m interface{}) error {
+	entries, changes, err := resourceLdapLdifRecords(d.Get("ldif").(string))
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	for _, record := range entries {
+		if err := client.Add(NewEntry(record)); err != nil {
+			return err
+		}
+		if d.Id() == "" { // Entries created so far are deleted on destroy if a later entry fails
+			d.SetId(record.DN)
+		}
+	}
+	for _, record := range changes {
+		if err := client.Apply(record); err != nil {
+			return err
+		}
+		if d.Id() == "" {
+			d.SetId(record.DN)
+		}
+	}
+	return resourceLdapLdifRead(d, client)
+}
+
+func resourceLdapLdifRead(d *schema.ResourceData, m interface{}) error {
+	entries, changes, err := resourceLdapLdifRecords(d.Get("ldif").(string))
+	if err != nil {
+		return err
+	}
+	client := m.(*Client)
+	var b strings.Builder
+	dns := make([]string, 0)
+	for _, record := range entries {
+		entry := NewEntry(record)
+		attributes, err := client.ReadEntry(record.DN, entry.Attributes.Keys())
+		if err != nil {
+			return err
+		}
+		if attributes == nil { // Deleted outside of Terraform
+			continue
+		}
+		entry.Attributes = resourceLdapLdifReadAttributes(entry.Attributes, *attributes)
+		resourceLdapLdifWriteEntry(&b, record, entry)
+		dns = append(dns, record.DN)
+	}
+	if len(entries) > 0 && len(dns) == 0 {
+		d.SetId("")
+		return nil
+	}
+	for _, record := range changes { // Applied once, so there is nothing to read back
+		resourceLdapLdifWriteChange(&b, record)
+	}
+	d.Set("dns", dns)
+	d.Set("ldif", b.String())
+	return nil
+}
+
+func resourceLdapLdifUpdate(d *schema.ResourceData, m interface{}) error {
+	o, n := d.GetChange("ldif")
+	oldRecords, oldChanges, err := resourceLdapLdifRecords(o.(string))
+	if err != nil {
+		return err
+	}
+	newRecords, newChanges, err := resourceLdapLdifRecords(n.(string))
+	if err != nil {
+		return err
+	}
+	oldEntries := make(map[string]*Entry)
+	for _, record := range oldRecords {
+		oldEntries[dnKey(record.DN)] = NewEntry(record)
+	}
+	newEntries := make(map[string]*Entry)
+	for _, record := range newRecords {
+		newEntries[dnKey(record.DN)] = NewEntry(record)
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
+	for i := len(oldRecords) - 1; i >= 0; i-- { // Children before parents
+		key := dnKey(oldRecords[i].DN)
+		if _, ok := newEntries[key]; !ok {
+			if err := client.Delete(oldEntries[key]); err != nil && !isResultCode(err, ldap.LDAPResultNoSuchObject) {
+				return err
+			}
+		}
+	}
+	for _, record := range newRecords { // Parents before children
+		newEntry := newEntries[dnKey(record.DN)]
+		oldEntry, ok := oldEntries[dnKey(record.DN)]
+		if !ok {
+			if err := client.Add(newEntry); err != nil {
+				return err
+			}
+			continue
+		}
+		// Compare attributes by the names used in the new document, keeping the DN the entry has
+		oldAttributes := oldEntry.Attributes.Select(newEntry.Attributes.Keys())
+		for _, key := range oldEntry.Attributes.Keys() {
+			if _, ok := newEntry.Attributes.Lookup(key); !ok {
+				oldAttributes.Map[key] = oldEntry.Attributes.Get(key)
+			}
+		}
+		newEntry.DN = oldEntry.DN
+		if err := client.Modify(&Entry{Attributes: oldAttributes, DN: oldEntry.DN}, newEntry); err != nil {
+			return err
+		}
+	}
+	applied := make(map[string]bool)
+	for _, key := range resourceLdapLdifChangeKeys(oldChanges) {
+		applied[key] = true
+	}
+	for i, key := range resourceLdapLdifChangeKeys(newChanges) { // Only changes added to the document
+		if !applied[key] {
+			if err := client.Apply(newChanges[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return resourceLdapLdifRead(d, client)
+}
+
+func resourceLdapLdifDelete(d *schema.ResourceData, m interface{}) error {
+	records, _, err := resourceLdapLdifRecords(d.Get("ldif").(string))
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	for i := len(records) - 1; i >= 0; i-- { // Children before parents
+		if err := client.Delete(NewEntry(records[i])); err != nil && !isResultCode(err, ldap.LDAPResultNoSuchObject) {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceLdapLdifRecords parses an LDIF document into the entries it describes, ordered parents before
+// children, and its modify, modrdn, moddn and delete change records, in document order.
+func resourceLdapLdifRecords(document string) (entries []internal.LDIFRecord, changes []internal.LDIFRecord, err error) {
+	records, err := internal.ParseLDIF(document)
+	if err != nil {
+		return nil, nil, err
+	}
+	depths := make(map[string]int)
+	for _, record := range records {
+		dn, err := ldap.ParseDN(record.DN)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: invalid distinguished name %q: %v", record.Line, record.DN, err)
+		}
+		if record.ChangeType != "" && record.ChangeType != "add" {
+			changes = append(changes, record)
+			continue
+		}
+		key := dnKey(record.DN)
+		if _, ok := depths[key]; ok {
+			return nil, nil, fmt.Errorf("line %d: entry %q is described more than once", record.Line, record.DN)
+		}
+		depths[key] = len(dn.RDNs)
+		entries = append(entries, record)
+	}
+	for _, record := range changes {
+		if _, ok := depths[dnKey(record.DN)]; ok {
+			return nil, nil, fmt.Errorf("line %d: entry %q is created by the document and cannot also be changed by it", record.Line, record.DN)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return depths[dnKey(entries[i].DN)] < depths[dnKey(entries[j].DN)]
+	})
+	return entries, changes, nil
+}
+
+// resourceLdapLdifReadAttributes returns the values read for the configured attributes. Passwords are write-only
+// or stored hashed, so their configured values are kept, and object classes are compared ignoring case and the
+// superclasses the server adds.
+func resourceLdapLdifReadAttributes(configured Attributes, read Attributes) Attributes {
+	attributes := read.Select(configured.Keys())
+	for _, key := range configured.Keys() {
+		switch {
+		case containsFold(sensitiveAttributes, key):
+			attributes.Map[key] = configured.Get(key)
+		case strings.EqualFold(key, "objectClass"):
+			values := make([]string, 0)
+			for _, value := range configured.Get(key) {
+				if containsFold(attributes.Get(key), value) {
+					values = append(values, value)
+				}
+			}
+			attributes.Map[key] = values
+		}
+	}
+	return attributes
+}
+
+// resourceLdapLdifWriteEntry writes the attributes of entry in the order they appear in record.
+func resourceLdapLdifWriteEntry(b *strings.Builder, record internal.LDIFRecord, entry *Entry) {
+	ldifWriteLine(b, "dn", record.DN)
+	written := make(map[string]bool)
+	for _, attribute := range record.Attributes {
+		key, ok := entry.Attributes.Lookup(attribute.Name)
+		if !ok || written[strings.ToLower(key)] {
+			continue
+		}
+		written[strings.ToLower(key)] = true
+		for _, value := range entry.Attributes.Get(key) {
+			ldifWriteLine(b, key, value)
+		}
+	}
+	b.WriteString("\n")
+}
+
+// resourceLdapLdifWriteChange writes a modify, modrdn, moddn or delete change record.
+func resourceLdapLdifWriteChange(b *strings.Builder, record internal.LDIFRecord) {
+	ldifWriteLine(b, "dn", record.DN)
+	ldifWriteLine(b, "changetype", record.ChangeType)
+	switch record.ChangeType {
+	case "modify":
+		for _, modification := range record.Modifications {
+			ldifWriteLine(b, modification.Operation, modification.Name)
+			for _, value := range modification.Values {
+				ldifWriteLine(b, modification.Name, value)
+			}
+			b.WriteString("-\n")
+		}
+	case "modrdn", "moddn":
+		ldifWriteLine(b, "newrdn", record.NewRDN)
+		if record.DeleteOldRDN {
+			b.WriteString("deleteoldrdn: 1\n")
+		} else {
+			b.WriteString("deleteoldrdn: 0\n")
+		}
+		if record.NewSuperior != "" {
+			ldifWriteLine(b, "newsuperior", record.NewSuperior)
+		}
+	}
+	b.WriteString("\n")
+}
+
+// resourceLdapLdifChangeKeys returns each change record in a form that ignores formatting and the case of its DN.
+func resourceLdapLdifChangeKeys(records []internal.LDIFRecord) []string {
+	keys := make([]string, len(records))
+	for i, record := range records {
+		record.DN = dnKey(record.DN)
+		var b strings.Builder
+		resourceLdapLdifWriteChange(&b, record)
+		keys[i] = b.String()
+	}
+	return keys
+}
+
+// resourceLdapLdifSuppressEquivalent ignores differences in formatting, the order of entries, attribute order
+// and value order between two LDIF documents. The order of change records is significant.
+func resourceLdapLdifSuppressEquivalent(k, old, new string, d *schema.ResourceData) bool {
+	oldRecords, oldChanges, err := resourceLdapLdifRecords(old)
+	if err != nil {
+		return false
+	}
+	newRecords, newChanges, err := resourceLdapLdifRecords(new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(resourceLdapLdifCanonical(oldRecords), resourceLdapLdifCanonical(newRecords)) &&
+		reflect.DeepEqual(resourceLdapLdifChangeKeys(oldChanges), resourceLdapLdifChangeKeys(newChanges))
+}
+
+func resourceLdapLdifCanonical(records []internal.LDIFRecord) map[string]map[string][]string {
+	entries := make(map[string]map[string][]string)
+	for _, record := range records {
+		attributes := make(map[string][]string)
+		for _, attribute := range record.Attributes {
+			name := strings.ToLower(attribute.Name)
+			attributes[name] = append(attributes[name], attribute.Value)
+		}
+		for _, values := range attributes {
+			sort.Strings(values)
+		}
+		entries[dnKey(record.DN)] = attributes
+	}
+	return entries
+}
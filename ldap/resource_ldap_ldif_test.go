@@ -0,0 +1,212 @@
+package ldap
+
+import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAccLdapLdif_basic(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	ou := "ou=People,dc=example,dc=com"
+	alice := "uid=alice,ou=People,dc=example,dc=com"
+	bob := "uid=bob,ou=People,dc=example,dc=com"
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, ou, alice, bob),
+		Steps: []resource.TestStep{
+			{
+				// Children may precede their parents, and formatting and comments are ignored
+				Config: testAccProviderConfig(server) + `
+resource "ldap_ldif" "people" {
+  ldif = <<EOT
+version: 1
+
+# Alice
+dn: uid=alice,ou=People,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: alice
+cn: Alice Smith
+sn:: U21pdGg=
+
+dn: ou=People,dc=example,dc=com
+changetype: add
+objectClass: organizationalUnit
+ou: People
+EOT
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_ldif.people", "dns.#", "2"),
+					resource.TestCheckResourceAttr("ldap_ldif.people", "dns.0", ou),
+					testAccCheckAttribute(server, alice, "sn", "Smith"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_ldif" "people" {
+  ldif = <<EOT
+dn: ou=People,dc=example,dc=com
+objectClass: organizationalUnit
+ou: People
+description: Staff
+
+dn: uid=bob,ou=People,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: bob
+cn: Bob Jones
+sn: Jones
+EOT
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDestroyed(server, alice),
+					testAccCheckAttribute(server, ou, "description", "Staff"),
+					testAccCheckAttribute(server, bob, "cn", "Bob Jones"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapLdif_serverNormalizedValues(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	alice := "uid=alice,dc=example,dc=com"
+	config := testAccProviderConfig(server) + `
+resource "ldap_ldif" "alice" {
+  ldif = <<EOT
+dn: uid=alice,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: alice
+cn: Alice Smith
+sn: Smith
+userPassword: secret
+EOT
+}
+`
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, alice),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testAccCheckAttribute(server, alice, "userPassword", "secret"),
+			},
+			{
+				// The password is hashed and superclasses are added, which is not drift
+				PreConfig: func() {
+					attributes := server.Get(alice)
+					attributes["objectClass"] = []string{"top", "person", "organizationalPerson", "inetorgperson"}
+					attributes["userPassword"] = []string{"{SSHA}cZfMWlxYVyXJ4mNHjdyCF0cBqQ5bEdq1"}
+					server.Put(alice, attributes)
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				// A password the server does not return is not drift either
+				PreConfig: func() {
+					attributes := server.Get(alice)
+					delete(attributes, "userPassword")
+					server.Put(alice, attributes)
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccLdapLdif_changeRecords(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	admins := "cn=admins,dc=example,dc=com"
+	alice := "uid=alice,dc=example,dc=com"
+	bob := "uid=bob,dc=example,dc=com"
+	robert := "uid=robert,dc=example,dc=com"
+	carol := "uid=carol,dc=example,dc=com"
+	server.Put(admins, map[string][]string{"objectClass": {"groupOfNames"}, "cn": {"admins"}, "member": {carol}})
+	server.Put(bob, map[string][]string{"objectClass": {"inetOrgPerson"}, "uid": {"bob"}, "cn": {"Bob"}, "sn": {"Jones"}})
+	server.Put(carol, map[string][]string{"objectClass": {"inetOrgPerson"}, "uid": {"carol"}, "cn": {"Carol"}, "sn": {"White"}})
+	document := `
+dn: uid=alice,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: alice
+cn: Alice Smith
+sn: Smith
+
+dn: cn=admins,dc=example,dc=com
+changetype: modify
+add: member
+member: uid=alice,dc=example,dc=com
+-
+delete: member
+member: uid=carol,dc=example,dc=com
+-
+
+dn: uid=bob,dc=example,dc=com
+changetype: modrdn
+newrdn: uid=robert
+deleteoldrdn: 1
+
+dn: uid=carol,dc=example,dc=com
+changetype: delete
+`
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, alice),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_ldif" "seed" {
+  ldif = <<EOT
+dn: uid=alice,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: alice
+cn: Alice Smith
+sn: Smith
+
+dn: uid=alice,dc=example,dc=com
+changetype: modify
+replace: sn
+sn: Jones
+-
+EOT
+}
+`,
+				ExpectError: regexp.MustCompile(`entry "uid=alice,dc=example,dc=com" is created by the document and cannot also be changed by it`),
+			},
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_ldif" "seed" {
+  ldif = <<EOT` + document + `EOT
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_ldif.seed", "dns.#", "1"),
+					testAccCheckAttribute(server, admins, "member", alice),
+					testAccCheckAttribute(server, robert, "uid", "robert"),
+					testAccCheckDestroyed(server, bob, carol),
+				),
+			},
+			{
+				// Only the change record added to the document is applied
+				Config: testAccProviderConfig(server) + `
+resource "ldap_ldif" "seed" {
+  ldif = <<EOT` + document + `
+dn: cn=admins,dc=example,dc=com
+changetype: modify
+replace: description
+description: Administrators
+-
+EOT
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAttribute(server, admins, "member", alice),
+					testAccCheckAttribute(server, admins, "description", "Administrators"),
+				),
+			},
+		},
+	})
+}
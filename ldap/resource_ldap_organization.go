@@ -0,0 +1,206 @@
+package ldap
+
+import (
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"strings"
+)
+
+func resourceLdapOrganization() *schema.Resource {
+	resource := &schema.Resource{
+		Create: resourceLdapOrganizationCreate,
+		Read:   resourceLdapOrganizationRead,
+		Update: resourceLdapOrganizationUpdate,
+		Delete: resourceLdapOrganizationDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"attributes": extraAttributesSchema(),
+			"city": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the town or city.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a description of the object.",
+			},
+			"ignore_attribute_changes": ignoreAttributeChangesSchema(),
+			"managed_attributes":       managedAttributesSchema(),
+			"o": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The organization name",
+				ForceNew:    true,
+			},
+			"object_class": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The list of classes from which this object is derived.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the X.500 path of the parent of the new object, relative to the provider's base DN unless fully qualified. Omit it for an organization at the root of a naming context, e.g. \"o=example\".",
+			},
+			"postal_code": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the postal code or zip code.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a state or province.",
+			},
+			"street_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a street address.",
+			},
+			"telephone_number": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a telephone number.",
+			},
+		},
+	}
+	resource.CustomizeDiff = customdiff.All(
+		pathCustomizeDiff,
+		extraAttributesCustomizeDiff(&Organization{}),
+		subschemaCustomizeDiff(resource.Schema, func(d resourceData, client *Client) (Object, error) {
+			_, obj, err := resourceLdapOrganizationUnmarshal(d)
+			return obj, err
+		}),
+	)
+	return resource
+}
+
+func resourceLdapOrganizationCreate(d *schema.ResourceData, m interface{}) error {
+	_, o, err := resourceLdapOrganizationUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	if err := client.Add(o); err != nil {
+		return err
+	}
+	return resourceLdapOrganizationRead(d, client)
+}
+
+func resourceLdapOrganizationRead(d *schema.ResourceData, m interface{}) error {
+	_, o, err := resourceLdapOrganizationUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client)
+	if err := client.Search(newAttributeFilter(o, d)); err != nil {
+		return err
+	}
+	return resourceLdapOrganizationMarshal(o, d)
+}
+
+func resourceLdapOrganizationUpdate(d *schema.ResourceData, m interface{}) error {
+	oldOrganization, newOrganization, err := resourceLdapOrganizationUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
+	if err := client.Modify(oldOrganization, newOrganization); err != nil {
+		return err
+	}
+	return resourceLdapOrganizationRead(d, client)
+}
+
+func resourceLdapOrganizationDelete(d *schema.ResourceData, m interface{}) error {
+	_, o, err := resourceLdapOrganizationUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	if err := client.Delete(o); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceLdapOrganizationMarshal(o *Organization, d *schema.ResourceData) error {
+	if d.Id() != o.DN {
+		d.SetId(o.DN)
+	}
+	d.Set("attributes", flattenExtraAttributes(o.ExtraAttributes))
+	d.Set("city", o.City)
+	d.Set("description", o.Description)
+	d.Set("o", o.Organization)
+	d.Set("object_class", o.ObjectClass)
+	d.Set("path", o.Path)
+	d.Set("postal_code", o.PostalCode)
+	d.Set("state", o.State)
+	d.Set("street_address", o.StreetAddress)
+	d.Set("telephone_number", o.TelephoneNumber)
+	return nil
+}
+
+func resourceLdapOrganizationUnmarshal(d resourceData) (oldOrganization *Organization, newOrganization *Organization, err error) {
+	newOrganization = &Organization{DN: d.Id()}
+	oldOrganization = &Organization{DN: d.Id()}
+	if _, ok := d.GetOk("o"); !ok { // Absent on import
+		rdn, path := splitDN(d.Id())
+		if !strings.HasPrefix(strings.ToLower(rdn), "o=") {
+			return nil, nil, errors.New("invalid distinguished name; expected prefix \"o=\"")
+		}
+		newOrganization.Organization = rdn[2:]
+		newOrganization.Path = path
+	} else {
+		properties := map[string]func(*Organization, interface{}){
+			"attributes":  func(o *Organization, v interface{}) { o.ExtraAttributes = expandExtraAttributes(v) },
+			"city":        func(o *Organization, v interface{}) { o.City = v.(string) },
+			"description": func(o *Organization, v interface{}) { o.Description = v.(string) },
+			"o":           func(o *Organization, v interface{}) { o.Organization = v.(string) },
+			"object_class": func(o *Organization, v interface{}) {
+				set := v.(*schema.Set)
+				if set.Len() > 0 {
+					objectClass := make([]string, 0)
+					for _, c := range set.List() {
+						objectClass = append(objectClass, c.(string))
+					}
+					o.ObjectClass = objectClass
+				} else {
+					o.ObjectClass = []string{top, ORGANIZATION}
+					for _, objectClass := range o.ObjectClass {
+						set.Add(objectClass)
+					}
+				}
+			},
+			"path":             func(o *Organization, v interface{}) { o.Path = v.(string) },
+			"postal_code":      func(o *Organization, v interface{}) { o.PostalCode = v.(string) },
+			"state":            func(o *Organization, v interface{}) { o.State = v.(string) },
+			"street_address":   func(o *Organization, v interface{}) { o.StreetAddress = v.(string) },
+			"telephone_number": func(o *Organization, v interface{}) { o.TelephoneNumber = v.(string) },
+		}
+		for property, fn := range properties {
+			newVal := d.Get(property)
+			fn(newOrganization, newVal)
+			if d.HasChange(property) {
+				oldVal, _ := d.GetChange(property)
+				fn(oldOrganization, oldVal)
+			} else {
+				fn(oldOrganization, newVal)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package ldap
+
+import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"testing"
+)
+
+func TestAccLdapOrganization_basic(t *testing.T) {
+	testAccEntry(t, []testAccEntryCase{
+		{
+			name:         "defaults",
+			server:       ldaptest.Config{},
+			resourceName: "ldap_organization.example",
+			dn:           "o=Example,dc=example,dc=com",
+			steps: []testAccEntryStep{
+				{
+					config: `
+resource "ldap_organization" "example" {
+  o    = "Example"
+  path = "dc=example,dc=com"
+  city = "Raleigh"
+}
+`,
+					attributes: map[string][]string{
+						"objectClass": {"top", "organization"},
+						"l":           {"Raleigh"},
+					},
+				},
+				{
+					config: `
+resource "ldap_organization" "example" {
+  o    = "Example"
+  path = "dc=example,dc=com"
+  city = "Durham"
+}
+`,
+					attributes: map[string][]string{"l": {"Durham"}},
+				},
+			},
+		},
+		{
+			// The root entry of a naming context has no parent
+			name:         "root",
+			server:       ldaptest.Config{Suffix: "o=Example"},
+			root:         true,
+			resourceName: "ldap_organization.example",
+			dn:           "o=Example",
+			steps: []testAccEntryStep{
+				{
+					config: `
+resource "ldap_organization" "example" {
+  o = "Example"
+}
+`,
+					attributes: map[string][]string{
+						"objectClass": {"top", "organization"},
+						"o":           {"Example"},
+					},
+					state: map[string]string{"path": ""},
+				},
+			},
+		},
+		{
+			name:         "objectClass",
+			server:       ldaptest.Config{},
+			resourceName: "ldap_organization.example",
+			dn:           "o=Example,dc=example,dc=com",
+			steps: []testAccEntryStep{
+				{
+					config: `
+resource "ldap_organization" "example" {
+  o            = "Example"
+  path         = "dc=example,dc=com"
+  object_class = ["top", "organization", "extensibleObject"]
+}
+`,
+					attributes: map[string][]string{"objectClass": {"top", "organization", "extensibleObject"}},
+				},
+			},
+		},
+	})
+}
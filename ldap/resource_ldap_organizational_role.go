@@ -0,0 +1,201 @@
+package ldap
+
+import (
+	"errors"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"strings"
+)
+
+func resourceLdapOrganizationalRole() *schema.Resource {
+	resource := &schema.Resource{
+		Create: resourceLdapOrganizationalRoleCreate,
+		Read:   resourceLdapOrganizationalRoleRead,
+		Update: resourceLdapOrganizationalRoleUpdate,
+		Delete: resourceLdapOrganizationalRoleDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"attributes": extraAttributesSchema(),
+			"cn": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name that represents the object. Used to perform searches",
+				ForceNew:    true,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a description of the object.",
+			},
+			"ignore_attribute_changes": ignoreAttributeChangesSchema(),
+			"managed_attributes":       managedAttributesSchema(),
+			"object_class": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The list of classes from which this object is derived.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the X.500 path of the OU or container where the new object is created, relative to the provider's base DN unless fully qualified.",
+			},
+			"role_occupants": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: internal.DistinguishedName(),
+				},
+				Set:         schema.HashString,
+				Description: "Specifies the distinguished names of the objects that fulfill the role.",
+			},
+			"telephone_number": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a telephone number.",
+			},
+		},
+	}
+	resource.CustomizeDiff = customdiff.All(
+		pathCustomizeDiff,
+		extraAttributesCustomizeDiff(&OrganizationalRole{}),
+		subschemaCustomizeDiff(resource.Schema, func(d resourceData, client *Client) (Object, error) {
+			_, obj, err := resourceLdapOrganizationalRoleUnmarshal(d)
+			return obj, err
+		}),
+	)
+	return resource
+}
+
+func resourceLdapOrganizationalRoleCreate(d *schema.ResourceData, m interface{}) error {
+	_, r, err := resourceLdapOrganizationalRoleUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	if err := client.Add(r); err != nil {
+		return err
+	}
+	return resourceLdapOrganizationalRoleRead(d, client)
+}
+
+func resourceLdapOrganizationalRoleRead(d *schema.ResourceData, m interface{}) error {
+	_, r, err := resourceLdapOrganizationalRoleUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client)
+	if err := client.Search(newAttributeFilter(r, d)); err != nil {
+		return err
+	}
+	return resourceLdapOrganizationalRoleMarshal(r, d)
+}
+
+func resourceLdapOrganizationalRoleUpdate(d *schema.ResourceData, m interface{}) error {
+	oldOrganizationalRole, newOrganizationalRole, err := resourceLdapOrganizationalRoleUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
+	if err := client.Modify(oldOrganizationalRole, newOrganizationalRole); err != nil {
+		return err
+	}
+	return resourceLdapOrganizationalRoleRead(d, client)
+}
+
+func resourceLdapOrganizationalRoleDelete(d *schema.ResourceData, m interface{}) error {
+	_, r, err := resourceLdapOrganizationalRoleUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	if err := client.Delete(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceLdapOrganizationalRoleMarshal(r *OrganizationalRole, d *schema.ResourceData) error {
+	if d.Id() != r.DN {
+		d.SetId(r.DN)
+	}
+	d.Set("attributes", flattenExtraAttributes(r.ExtraAttributes))
+	d.Set("cn", r.CommonName)
+	d.Set("description", r.Description)
+	d.Set("object_class", r.ObjectClass)
+	d.Set("path", r.Path)
+	d.Set("role_occupants", r.RoleOccupants)
+	d.Set("telephone_number", r.TelephoneNumber)
+	return nil
+}
+
+func resourceLdapOrganizationalRoleUnmarshal(d resourceData) (oldOrganizationalRole *OrganizationalRole, newOrganizationalRole *OrganizationalRole, err error) {
+	newOrganizationalRole = &OrganizationalRole{DN: d.Id()}
+	oldOrganizationalRole = &OrganizationalRole{DN: d.Id()}
+	if _, ok := d.GetOk("path"); !ok { // Absent on import
+		rdn, path, err := internal.ParseDN(d.Id())
+		if err != nil {
+			return oldOrganizationalRole, newOrganizationalRole, err
+		}
+		if !strings.HasPrefix(strings.ToLower(rdn), "cn=") {
+			return nil, nil, errors.New("invalid distinguished name; expected prefix \"cn=\"")
+		}
+		newOrganizationalRole.CommonName = rdn[3:]
+		newOrganizationalRole.Path = path
+	} else {
+		properties := map[string]func(*OrganizationalRole, interface{}){
+			"attributes":  func(r *OrganizationalRole, v interface{}) { r.ExtraAttributes = expandExtraAttributes(v) },
+			"cn":          func(r *OrganizationalRole, v interface{}) { r.CommonName = v.(string) },
+			"description": func(r *OrganizationalRole, v interface{}) { r.Description = v.(string) },
+			"object_class": func(r *OrganizationalRole, v interface{}) {
+				set := v.(*schema.Set)
+				if set.Len() > 0 {
+					objectClass := make([]string, 0)
+					for _, c := range set.List() {
+						objectClass = append(objectClass, c.(string))
+					}
+					r.ObjectClass = objectClass
+				} else {
+					r.ObjectClass = []string{top, ORGANIZATIONAL_ROLE}
+					for _, objectClass := range r.ObjectClass {
+						set.Add(objectClass)
+					}
+				}
+			},
+			"path": func(r *OrganizationalRole, v interface{}) { r.Path = v.(string) },
+			"role_occupants": func(r *OrganizationalRole, v interface{}) {
+				set := v.(*schema.Set)
+				roleOccupants := make([]string, 0)
+				for _, e := range set.List() {
+					roleOccupants = append(roleOccupants, e.(string))
+				}
+				r.RoleOccupants = roleOccupants
+			},
+			"telephone_number": func(r *OrganizationalRole, v interface{}) { r.TelephoneNumber = v.(string) },
+		}
+		for property, fn := range properties {
+			newVal := d.Get(property)
+			fn(newOrganizationalRole, newVal)
+			if d.HasChange(property) {
+				oldVal, _ := d.GetChange(property)
+				fn(oldOrganizationalRole, oldVal)
+			} else {
+				fn(oldOrganizationalRole, newVal)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,71 @@
+package ldap
+
+import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"testing"
+)
+
+func TestAccLdapOrganizationalRole_basic(t *testing.T) {
+	testAccEntry(t, []testAccEntryCase{
+		{
+			name:         "defaults",
+			server:       ldaptest.Config{},
+			resourceName: "ldap_organizational_role.admin",
+			dn:           "cn=Directory Administrator,dc=example,dc=com",
+			steps: []testAccEntryStep{
+				{
+					config: `
+resource "ldap_organizational_role" "admin" {
+  cn             = "Directory Administrator"
+  path           = "dc=example,dc=com"
+  role_occupants = ["uid=alice,dc=example,dc=com"]
+}
+`,
+					attributes: map[string][]string{
+						"objectClass":  {"top", "organizationalRole"},
+						"roleOccupant": {"uid=alice,dc=example,dc=com"},
+					},
+				},
+				{
+					config: `
+resource "ldap_organizational_role" "admin" {
+  cn             = "Directory Administrator"
+  path           = "dc=example,dc=com"
+  role_occupants = ["uid=alice,dc=example,dc=com", "uid=bob,dc=example,dc=com"]
+}
+`,
+					attributes: map[string][]string{
+						"roleOccupant": {"uid=alice,dc=example,dc=com", "uid=bob,dc=example,dc=com"},
+					},
+				},
+				{
+					config: `
+resource "ldap_organizational_role" "admin" {
+  cn   = "Directory Administrator"
+  path = "dc=example,dc=com"
+}
+`,
+					attributes: map[string][]string{"roleOccupant": nil},
+				},
+			},
+		},
+		{
+			name:         "objectClass",
+			server:       ldaptest.Config{},
+			resourceName: "ldap_organizational_role.admin",
+			dn:           "cn=Directory Administrator,dc=example,dc=com",
+			steps: []testAccEntryStep{
+				{
+					config: `
+resource "ldap_organizational_role" "admin" {
+  cn           = "Directory Administrator"
+  path         = "dc=example,dc=com"
+  object_class = ["top", "organizationalRole", "extensibleObject"]
+}
+`,
+					attributes: map[string][]string{"objectClass": {"top", "organizationalRole", "extensibleObject"}},
+				},
+			},
+		},
+	})
+}
@@ -2,21 +2,30 @@ package ldap
 
 import (
 	"errors"
+	"fmt"
 	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"strings"
 )
 
 func resourceLdapOrganizationalUnit() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		Create: resourceLdapOrganizationalUnitCreate,
 		Read:   resourceLdapOrganizationalUnitRead,
 		Update: resourceLdapOrganizationalUnitUpdate,
 		Delete: resourceLdapOrganizationalUnitDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
 		Schema: map[string]*schema.Schema{
+			"attributes": extraAttributesSchema(),
 			"city": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -32,6 +41,8 @@ func resourceLdapOrganizationalUnit() *schema.Resource {
 				Optional:    true,
 				Description: "Specifies a description of the object.",
 			},
+			"ignore_attribute_changes": ignoreAttributeChangesSchema(),
+			"managed_attributes":       managedAttributesSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -56,14 +67,27 @@ func resourceLdapOrganizationalUnit() *schema.Resource {
 			"path": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "Specifies the X.500 path of the OU or container where the new object is created.",
-				ForceNew:    true,
+				Description: "Specifies the X.500 path of the OU or container where the new object is created, relative to the provider's base DN unless fully qualified.",
 			},
 			"postal_code": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "Specifies the postal code or zip code.",
 			},
+			"prevent_destroy_if_not_empty": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"recursive_delete"},
+				Description:   "Specifies that destroying the organizational unit fails with a listing of its children when it is not empty.",
+			},
+			"recursive_delete": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"prevent_destroy_if_not_empty"},
+				Description:   "Specifies that destroying the organizational unit also deletes all of its children.",
+			},
 			"street_address": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -76,6 +100,15 @@ func resourceLdapOrganizationalUnit() *schema.Resource {
 			},
 		},
 	}
+	resource.CustomizeDiff = customdiff.All(
+		pathCustomizeDiff,
+		extraAttributesCustomizeDiff(&OrganizationalUnit{}),
+		subschemaCustomizeDiff(resource.Schema, func(d resourceData, client *Client) (Object, error) {
+			_, obj, err := resourceLdapOrganizationalUnitUnmarshal(d)
+			return obj, err
+		}),
+	)
+	return resource
 }
 
 func resourceLdapOrganizationalUnitCreate(d *schema.ResourceData, m interface{}) error {
@@ -83,11 +116,12 @@ func resourceLdapOrganizationalUnitCreate(d *schema.ResourceData, m interface{})
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
 	if err := client.Add(ou); err != nil {
 		return err
 	}
-	return resourceLdapOrganizationalUnitRead(d, m)
+	return resourceLdapOrganizationalUnitRead(d, client)
 }
 
 func resourceLdapOrganizationalUnitRead(d *schema.ResourceData, m interface{}) error {
@@ -96,7 +130,7 @@ func resourceLdapOrganizationalUnitRead(d *schema.ResourceData, m interface{}) e
 		return err
 	}
 	client := m.(*Client)
-	if err := client.Search(ou); err != nil {
+	if err := client.Search(newAttributeFilter(ou, d)); err != nil {
 		return err
 	}
 	return resourceLdapOrganizationalUnitMarshal(ou, d)
@@ -107,26 +141,62 @@ func resourceLdapOrganizationalUnitUpdate(d *schema.ResourceData, m interface{})
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
 	if err := client.Modify(oldOu, newOu); err != nil {
 		return err
 	}
-	return resourceLdapOrganizationalUnitRead(d, m)
+	return resourceLdapOrganizationalUnitRead(d, client)
 }
 
 func resourceLdapOrganizationalUnitDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*Client)
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
 	_, ou, _ := resourceLdapOrganizationalUnitUnmarshal(d)
+	if d.Get("prevent_destroy_if_not_empty").(bool) {
+		children, err := client.Children(ou.GetDN())
+		if err != nil {
+			return err
+		}
+		if len(children) > 0 {
+			return resourceLdapOrganizationalUnitNotEmpty(ou, children)
+		}
+	}
+	if d.Get("recursive_delete").(bool) {
+		return client.DeleteTree(ou)
+	}
 	if err := client.Delete(ou); err != nil {
+		if isResultCode(err, ldap.LDAPResultNotAllowedOnNonLeaf) {
+			if children, childErr := client.Children(ou.GetDN()); childErr == nil && len(children) > 0 {
+				return resourceLdapOrganizationalUnitNotEmpty(ou, children)
+			}
+		} else if isResultCode(err, ldap.LDAPResultInsufficientAccessRights) {
+			return fmt.Errorf("%w\nthe organizational unit may be protected from accidental deletion", err)
+		}
 		return err
 	}
 	return nil
 }
 
+// resourceLdapOrganizationalUnitNotEmpty describes the children preventing ou from being deleted.
+func resourceLdapOrganizationalUnitNotEmpty(ou *OrganizationalUnit, children []string) error {
+	const maxListed = 25
+	listed := children
+	if len(listed) > maxListed {
+		listed = listed[:maxListed]
+	}
+	message := fmt.Sprintf("organizational unit %q has %d children; delete them or set recursive_delete:\n  %s",
+		ou.GetDN(), len(children), strings.Join(listed, "\n  "))
+	if len(children) > maxListed {
+		message += fmt.Sprintf("\n  ... and %d more", len(children)-maxListed)
+	}
+	return errors.New(message)
+}
+
 func resourceLdapOrganizationalUnitMarshal(ou *OrganizationalUnit, d *schema.ResourceData) error {
 	if d.Id() != ou.DN {
 		d.SetId(ou.DN)
 	}
+	d.Set("attributes", flattenExtraAttributes(ou.ExtraAttributes))
 	d.Set("city", ou.City)
 	d.Set("country", ou.Country)
 	d.Set("description", ou.Description)
@@ -140,7 +210,7 @@ func resourceLdapOrganizationalUnitMarshal(ou *OrganizationalUnit, d *schema.Res
 	return nil
 }
 
-func resourceLdapOrganizationalUnitUnmarshal(d *schema.ResourceData) (oldOu *OrganizationalUnit, newOu *OrganizationalUnit, err error) {
+func resourceLdapOrganizationalUnitUnmarshal(d resourceData) (oldOu *OrganizationalUnit, newOu *OrganizationalUnit, err error) {
 	newOu = &OrganizationalUnit{DN: d.Id()}
 	oldOu = &OrganizationalUnit{DN: d.Id()}
 	if _, ok := d.GetOk("path"); !ok { // Absent on import
@@ -155,6 +225,7 @@ func resourceLdapOrganizationalUnitUnmarshal(d *schema.ResourceData) (oldOu *Org
 		newOu.Path = path
 	} else {
 		properties := map[string]func(*OrganizationalUnit, interface{}){
+			"attributes":  func(ou *OrganizationalUnit, v interface{}) { ou.ExtraAttributes = expandExtraAttributes(v) },
 			"city":        func(ou *OrganizationalUnit, v interface{}) { ou.City = v.(string) },
 			"country":     func(ou *OrganizationalUnit, v interface{}) { ou.Country = v.(string) },
 			"description": func(ou *OrganizationalUnit, v interface{}) { ou.Description = v.(string) },
@@ -0,0 +1,216 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"regexp"
+	"testing"
+)
+
+func TestAccLdapOrganizationalUnit_basic(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "ou=People,dc=example,dc=com"
+	config := testAccProviderConfig(server) + `
+resource "ldap_organizational_unit" "people" {
+  ou   = "People"
+  path = "dc=example,dc=com"
+  city = "Springfield"
+}
+`
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_organizational_unit" "people" {
+  ou          = "People"
+  path        = "dc=example,dc=com"
+  description = "Staff accounts"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_organizational_unit.people", "id", dn),
+					resource.TestCheckResourceAttr("ldap_organizational_unit.people", "description", "Staff accounts"),
+					testAccCheckAttribute(server, dn, "objectClass", "top", "organizationalUnit"),
+					testAccCheckAttribute(server, dn, "description", "Staff accounts"),
+				),
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_organizational_unit.people", "description", ""),
+					testAccCheckAttribute(server, dn, "description"),
+					testAccCheckAttribute(server, dn, "l", "Springfield"),
+				),
+			},
+			{
+				Config:                  config,
+				ResourceName:            "ldap_organizational_unit.people",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"prevent_destroy_if_not_empty", "recursive_delete"},
+			},
+		},
+	})
+}
+
+func TestAccLdapOrganizationalUnit_move(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	config := func(parent string) string {
+		return testAccProviderConfig(server) + `
+resource "ldap_organizational_unit" "sales" {
+  ou   = "Sales"
+  path = "dc=example,dc=com"
+}
+
+resource "ldap_organizational_unit" "marketing" {
+  ou   = "Marketing"
+  path = "dc=example,dc=com"
+}
+
+resource "ldap_organizational_unit" "team" {
+  ou   = "Team"
+  path = ldap_organizational_unit.` + parent + `.id
+}
+`
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, "ou=Sales,dc=example,dc=com", "ou=Marketing,dc=example,dc=com"),
+		Steps: []resource.TestStep{
+			{
+				Config: config("sales"),
+				Check:  resource.TestCheckResourceAttr("ldap_organizational_unit.team", "id", "ou=Team,ou=Sales,dc=example,dc=com"),
+			},
+			{
+				Config: config("marketing"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_organizational_unit.team", "id", "ou=Team,ou=Marketing,dc=example,dc=com"),
+					testAccCheckDestroyed(server, "ou=Team,ou=Sales,dc=example,dc=com"),
+					testAccCheckAttribute(server, "ou=Team,ou=Marketing,dc=example,dc=com", "ou", "Team"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapOrganizationalUnit_relativePath(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	config := func(path string) string {
+		return testAccProviderConfig(server) + `
+resource "ldap_organizational_unit" "sales" {
+  ou   = "Sales"
+  path = "dc=example,dc=com"
+}
+
+resource "ldap_organizational_unit" "team" {
+  ou         = "Team"
+  path       = "` + path + `"
+  depends_on = [ldap_organizational_unit.sales]
+}
+`
+	}
+	dn := "ou=Team,ou=Sales,dc=example,dc=com"
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: config("ou=Sales"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_organizational_unit.team", "id", dn),
+					testAccCheckAttribute(server, dn, "ou", "Team"),
+				),
+			},
+			{
+				Config: config("ou=Sales,dc=example,dc=com"),
+				Check:  resource.TestCheckResourceAttr("ldap_organizational_unit.team", "id", dn),
+			},
+		},
+	})
+}
+
+func TestAccLdapOrganizationalUnit_retry(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "ou=People,dc=example,dc=com"
+	server.Fail(ldaptest.Fault{Operation: ldaptest.OperationBind, ResultCode: ldap.LDAPResultBusy, Count: 1})
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server, "max_retries = 1") + `
+resource "ldap_organizational_unit" "people" {
+  ou   = "People"
+  path = "dc=example,dc=com"
+}
+`,
+				Check: testAccCheckAttribute(server, dn, "ou", "People"),
+			},
+		},
+	})
+}
+
+func TestAccLdapOrganizationalUnit_error(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "ou=People,dc=example,dc=com"
+	server.Fail(ldaptest.Fault{Operation: ldaptest.OperationAdd, DN: dn, ResultCode: ldap.LDAPResultInsufficientAccessRights,
+		Message: "00002098: SecErr: DSID-03150F94, problem 4003 (INSUFF_ACCESS_RIGHTS), data 0"})
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_organizational_unit" "people" {
+  ou   = "People"
+  path = "dc=example,dc=com"
+}
+`,
+				ExpectError: regexp.MustCompile(`LDAP add "ou=People,dc=example,dc=com" failed: Insufficient Access Rights \(50\)(.|\n)*ERROR_DS_INSUFF_ACCESS_RIGHTS`),
+			},
+		},
+	})
+}
+
+func TestAccLdapOrganizationalUnit_recursiveDelete(t *testing.T) {
+	// More children than the server returns without paging
+	server := testAccServer(t, ldaptest.Config{MaxPageSize: 2})
+	dn := "ou=People,dc=example,dc=com"
+	children := []string{"uid=alice," + dn, "uid=bob," + dn, "uid=carol," + dn}
+	config := func(argument string) string {
+		return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "ldap_organizational_unit" "people" {
+  ou   = "People"
+  path = "dc=example,dc=com"
+  %s = true
+}
+`, argument)
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, append(children, dn)...),
+		Steps: []resource.TestStep{
+			{
+				Config: config("prevent_destroy_if_not_empty"),
+				Check: func(*terraform.State) error {
+					for _, child := range children {
+						server.Put(child, map[string][]string{"objectClass": {"account"}})
+					}
+					return nil
+				},
+			},
+			{
+				Config:      testAccProviderConfig(server),
+				ExpectError: regexp.MustCompile(`organizational unit "ou=People,dc=example,dc=com" has 3 children`),
+			},
+			{
+				Config: config("recursive_delete"),
+			},
+		},
+	})
+}
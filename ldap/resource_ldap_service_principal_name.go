@@ -0,0 +1,145 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+func resourceLdapServicePrincipalName() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLdapServicePrincipalNameCreate,
+		Read:   resourceLdapServicePrincipalNameRead,
+		Delete: resourceLdapServicePrincipalNameDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"account": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Specifies the distinguished name of the user or computer account the SPN is registered on.",
+				ForceNew:     true,
+				ValidateFunc: internal.DistinguishedName(),
+			},
+			"search_base": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Specifies the X.500 path searched for duplicate SPNs. Defaults to the whole forest, searched through the Global Catalog, in Active Directory and to the base DN elsewhere.",
+				ForceNew:     true,
+				ValidateFunc: internal.DistinguishedName(),
+			},
+			"spn": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Specifies the service principal name in the format <service class>/<host>[:<port>][/<service name>].",
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[^/\s]+/[^/\s]+(/[^/\s]+)?$`), "must be in the format <service class>/<host>[:<port>][/<service name>]"),
+			},
+		},
+	}
+}
+
+func resourceLdapServicePrincipalNameCreate(d *schema.ResourceData, m interface{}) error {
+	spn := &ServicePrincipalName{
+		Account: d.Get("account").(string),
+		Value:   d.Get("spn").(string),
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	// Duplicate SPNs break Kerberos authentication for every account that holds them, in any domain
+	search, bases := client, []string{d.Get("search_base").(string)}
+	if bases[0] == "" {
+		switch {
+		case client.Flavor == FLAVOR_ACTIVE_DIRECTORY:
+			gc, err := client.globalCatalog()
+			if err != nil {
+				return err
+			}
+			search = gc
+		case client.BaseDN != "":
+			bases = []string{client.BaseDN}
+		default:
+			bases = client.RootDSE.Get("namingContexts")
+		}
+	}
+	owners := make([]string, 0)
+	for _, base := range bases {
+		found, err := resourceLdapServicePrincipalNameOwners(search, base, spn.Value)
+		if err != nil {
+			return err
+		}
+		owners = append(owners, found...)
+	}
+	if len(owners) > 0 {
+		return fmt.Errorf("service principal name %q is already registered on %s", spn.Value, strings.Join(owners, "; "))
+	}
+	if err := client.AddValues(spn.Account, "servicePrincipalName", []string{spn.Value}); err != nil {
+		return err
+	}
+	d.SetId(spn.GetID())
+	return resourceLdapServicePrincipalNameRead(d, client)
+}
+
+func resourceLdapServicePrincipalNameRead(d *schema.ResourceData, m interface{}) error {
+	spn := &ServicePrincipalName{}
+	if err := spn.SetID(d.Id()); err != nil {
+		return err
+	}
+	client := m.(*Client)
+	owners, err := resourceLdapServicePrincipalNameOwners(client, spn.Account, spn.Value)
+	if err != nil && !isResultCode(err, ldap.LDAPResultNoSuchObject) { // The account may have been deleted
+		return err
+	}
+	found := false
+	for _, owner := range owners {
+		if strings.EqualFold(owner, spn.Account) {
+			found = true
+		}
+	}
+	if !found { // Removed outside of Terraform
+		d.SetId("")
+		return nil
+	}
+	d.Set("account", spn.Account)
+	d.Set("spn", spn.Value)
+	return nil
+}
+
+func resourceLdapServicePrincipalNameDelete(d *schema.ResourceData, m interface{}) error {
+	spn := &ServicePrincipalName{}
+	if err := spn.SetID(d.Id()); err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	err := client.DeleteValues(spn.Account, "servicePrincipalName", []string{spn.Value})
+	if isResultCode(err, ldap.LDAPResultNoSuchObject) { // Deleted with the account
+		return nil
+	}
+	return err
+}
+
+// resourceLdapServicePrincipalNameOwners returns the DNs of the accounts beneath base holding value. An
+// empty base searches every naming context of a Global Catalog.
+func resourceLdapServicePrincipalNameOwners(client *Client, base string, value string) ([]string, error) {
+	filter := fmt.Sprintf("(servicePrincipalName=%s)", ldap.EscapeFilter(value))
+	entries, err := client.SearchEntries(base, filter, []string{"servicePrincipalName"})
+	if err != nil {
+		return nil, err
+	}
+	owners := make([]string, 0, len(entries))
+	for dn := range entries {
+		owners = append(owners, dn)
+	}
+	sort.Strings(owners)
+	return owners, nil
+}
@@ -0,0 +1,106 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAccLdapServicePrincipalName_basic(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{ActiveDirectory: true})
+	account := "cn=svc_web,dc=example,dc=com"
+	server.Put(account, map[string][]string{"objectClass": {"top", "user"}, "cn": {"svc_web"}, "servicePrincipalName": {"HOST/web"}})
+	// Held by an account in another domain of the forest, found only through the Global Catalog
+	server.Put("cn=web,dc=child,dc=example,dc=org", map[string][]string{"objectClass": {"top", "computer"}, "cn": {"web"}, "servicePrincipalName": {"HTTP/web.example.com"}})
+	config := func(spn string) string {
+		return testAccProviderConfig(server, fmt.Sprintf("global_catalog = %q", server.URL)) + fmt.Sprintf(`
+resource "ldap_service_principal_name" "web" {
+  account = %q
+  spn     = %q
+}
+`, account, spn)
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckAttribute(server, account, "servicePrincipalName", "HOST/web"),
+		Steps: []resource.TestStep{
+			{
+				Config:      config("HTTP/web.example.com"),
+				ExpectError: regexp.MustCompile(`already registered on cn=web,dc=child,dc=example,dc=org`),
+			},
+			{
+				Config: config("HTTP/app.example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_service_principal_name.web", "id", account+"|HTTP/app.example.com"),
+					testAccCheckAttribute(server, account, "servicePrincipalName", "HOST/web", "HTTP/app.example.com"),
+				),
+			},
+			{
+				Config:            config("HTTP/app.example.com"),
+				ResourceName:      "ldap_service_principal_name.web",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccLdapServicePrincipalName_baseDN(t *testing.T) {
+	// Servers other than Active Directory have no Global Catalog, so the base DN is searched instead
+	server := testAccServer(t, ldaptest.Config{})
+	account := "uid=svc_web,dc=example,dc=com"
+	server.Put(account, map[string][]string{"objectClass": {"account"}, "uid": {"svc_web"}})
+	server.Put("uid=web,ou=hosts,dc=example,dc=com", map[string][]string{"objectClass": {"account"}, "uid": {"web"}, "servicePrincipalName": {"HTTP/web.example.com"}})
+	config := func(spn string) string {
+		return testAccProviderConfig(server) + fmt.Sprintf(`
+resource "ldap_service_principal_name" "web" {
+  account = %q
+  spn     = %q
+}
+`, account, spn)
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckAttribute(server, account, "servicePrincipalName"),
+		Steps: []resource.TestStep{
+			{
+				Config:      config("HTTP/web.example.com"),
+				ExpectError: regexp.MustCompile(`already registered on uid=web,ou=hosts,dc=example,dc=com`),
+			},
+			{
+				Config: config("HTTP/app.example.com"),
+				Check:  testAccCheckAttribute(server, account, "servicePrincipalName", "HTTP/app.example.com"),
+			},
+		},
+	})
+}
+
+func TestAccLdapServicePrincipalName_accountDeleted(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	account := "uid=svc_web,dc=example,dc=com"
+	server.Put(account, map[string][]string{"objectClass": {"account"}, "uid": {"svc_web"}})
+	config := testAccProviderConfig(server) + fmt.Sprintf(`
+resource "ldap_service_principal_name" "web" {
+  account = %q
+  spn     = "HTTP/app.example.com"
+}
+`, account)
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, account),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				// Refreshing and destroying succeed once the account is deleted outside of Terraform
+				PreConfig:          func() { server.Delete(account) },
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
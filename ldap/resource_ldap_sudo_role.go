@@ -0,0 +1,340 @@
+package ldap
+
+import (
+	"errors"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// sudoCommandValidation accepts ALL, sudoedit or a fully-qualified command, optionally negated or with a digest
+	sudoCommandValidation = validation.StringMatch(
+		regexp.MustCompile(`^!?(ALL|sudoedit\s+\S.*|(sha(224|256|384|512):\S+\s+)?/\S.*)$`),
+		"must be \"ALL\", \"sudoedit\" with arguments or a fully-qualified command, optionally prefixed with \"!\" or a digest")
+	// sudoEntryValidation accepts a single, optionally negated, user, group, netgroup or host
+	sudoEntryValidation = validation.StringMatch(
+		regexp.MustCompile(`^!?\S+$`),
+		"must be a single user, group, netgroup or host without whitespace, optionally prefixed with \"!\"")
+)
+
+func resourceLdapSudoRole() *schema.Resource {
+	resource := &schema.Resource{
+		Create: resourceLdapSudoRoleCreate,
+		Read:   resourceLdapSudoRoleRead,
+		Update: resourceLdapSudoRoleUpdate,
+		Delete: resourceLdapSudoRoleDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"attributes": extraAttributesSchema(),
+			"cn": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name that represents the object. Used to perform searches",
+				ForceNew:    true,
+			},
+			"commands": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: sudoCommandValidation,
+				},
+				Set:         schema.HashString,
+				Description: "Specifies the commands that may be run, or \"ALL\".",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a description of the object.",
+			},
+			"hosts": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: sudoEntryValidation,
+				},
+				Set:         schema.HashString,
+				Description: "Specifies the hosts, IP addresses, networks or netgroups the rule applies to, or \"ALL\".",
+			},
+			"ignore_attribute_changes": ignoreAttributeChangesSchema(),
+			"managed_attributes":       managedAttributesSchema(),
+			"not_after": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Specifies the RFC 3339 time after which the rule no longer applies.",
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: resourceLdapSudoRoleTimeDiffSuppress,
+			},
+			"not_before": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Specifies the RFC 3339 time before which the rule does not apply.",
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: resourceLdapSudoRoleTimeDiffSuppress,
+			},
+			"object_class": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The list of classes from which this object is derived.",
+			},
+			"options": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+				},
+				Set:         schema.HashString,
+				Description: "Specifies sudoers options applied by the rule.",
+			},
+			"order": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Specifies the order in which rules are applied; higher values take precedence.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Specifies the X.500 path of the OU or container where the new object is created, relative to the provider's base DN unless fully qualified.",
+			},
+			"run_as_groups": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: sudoEntryValidation,
+				},
+				Set:         schema.HashString,
+				Description: "Specifies the groups commands may be run as.",
+			},
+			"run_as_users": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: sudoEntryValidation,
+				},
+				Set:         schema.HashString,
+				Description: "Specifies the users commands may be run as.",
+			},
+			"users": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: sudoEntryValidation,
+				},
+				Set:         schema.HashString,
+				Description: "Specifies the users, groups (%group) or netgroups (+netgroup) the rule applies to, or \"ALL\".",
+			},
+		},
+	}
+	resource.CustomizeDiff = customdiff.All(
+		pathCustomizeDiff,
+		extraAttributesCustomizeDiff(&SudoRole{}),
+		subschemaCustomizeDiff(resource.Schema, func(d resourceData, client *Client) (Object, error) {
+			_, obj, err := resourceLdapSudoRoleUnmarshal(d)
+			return obj, err
+		}),
+	)
+	return resource
+}
+
+func resourceLdapSudoRoleCreate(d *schema.ResourceData, m interface{}) error {
+	_, s, err := resourceLdapSudoRoleUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	if err := client.Add(s); err != nil {
+		return err
+	}
+	return resourceLdapSudoRoleRead(d, client)
+}
+
+func resourceLdapSudoRoleRead(d *schema.ResourceData, m interface{}) error {
+	_, s, err := resourceLdapSudoRoleUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client)
+	if err := client.Search(newAttributeFilter(s, d)); err != nil {
+		return err
+	}
+	return resourceLdapSudoRoleMarshal(s, d)
+}
+
+func resourceLdapSudoRoleUpdate(d *schema.ResourceData, m interface{}) error {
+	oldSudoRole, newSudoRole, err := resourceLdapSudoRoleUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
+	if err := client.Modify(oldSudoRole, newSudoRole); err != nil {
+		return err
+	}
+	return resourceLdapSudoRoleRead(d, client)
+}
+
+func resourceLdapSudoRoleDelete(d *schema.ResourceData, m interface{}) error {
+	_, s, err := resourceLdapSudoRoleUnmarshal(d)
+	if err != nil {
+		return err
+	}
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	if err := client.Delete(s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceLdapSudoRoleMarshal(s *SudoRole, d *schema.ResourceData) error {
+	if d.Id() != s.DN {
+		d.SetId(s.DN)
+	}
+	d.Set("attributes", flattenExtraAttributes(s.ExtraAttributes))
+	d.Set("cn", s.CommonName)
+	d.Set("commands", s.Commands)
+	d.Set("description", s.Description)
+	d.Set("hosts", s.Hosts)
+	d.Set("not_after", s.NotAfter)
+	d.Set("not_before", s.NotBefore)
+	d.Set("object_class", s.ObjectClass)
+	d.Set("options", s.Options)
+	d.Set("order", s.Order)
+	d.Set("path", s.Path)
+	d.Set("run_as_groups", s.RunAsGroups)
+	d.Set("run_as_users", s.RunAsUsers)
+	d.Set("users", s.Users)
+	return nil
+}
+
+func resourceLdapSudoRoleUnmarshal(d resourceData) (oldSudoRole *SudoRole, newSudoRole *SudoRole, err error) {
+	newSudoRole = &SudoRole{DN: d.Id()}
+	oldSudoRole = &SudoRole{DN: d.Id()}
+	if _, ok := d.GetOk("path"); !ok { // Absent on import
+		rdn, path, err := internal.ParseDN(d.Id())
+		if err != nil {
+			return oldSudoRole, newSudoRole, err
+		}
+		if !strings.HasPrefix(strings.ToLower(rdn), "cn=") {
+			return nil, nil, errors.New("invalid distinguished name; expected prefix \"cn=\"")
+		}
+		newSudoRole.CommonName = rdn[3:]
+		newSudoRole.Path = path
+	} else {
+		properties := map[string]func(*SudoRole, interface{}){
+			"attributes": func(s *SudoRole, v interface{}) { s.ExtraAttributes = expandExtraAttributes(v) },
+			"cn":         func(s *SudoRole, v interface{}) { s.CommonName = v.(string) },
+			"commands": func(s *SudoRole, v interface{}) {
+				set := v.(*schema.Set)
+				commands := make([]string, 0)
+				for _, e := range set.List() {
+					commands = append(commands, e.(string))
+				}
+				s.Commands = commands
+			},
+			"description": func(s *SudoRole, v interface{}) { s.Description = v.(string) },
+			"hosts": func(s *SudoRole, v interface{}) {
+				set := v.(*schema.Set)
+				hosts := make([]string, 0)
+				for _, e := range set.List() {
+					hosts = append(hosts, e.(string))
+				}
+				s.Hosts = hosts
+			},
+			"not_after":  func(s *SudoRole, v interface{}) { s.NotAfter = v.(string) },
+			"not_before": func(s *SudoRole, v interface{}) { s.NotBefore = v.(string) },
+			"object_class": func(s *SudoRole, v interface{}) {
+				set := v.(*schema.Set)
+				if set.Len() > 0 {
+					objectClass := make([]string, 0)
+					for _, c := range set.List() {
+						objectClass = append(objectClass, c.(string))
+					}
+					s.ObjectClass = objectClass
+				} else {
+					s.ObjectClass = []string{top, SUDO_ROLE}
+					for _, objectClass := range s.ObjectClass {
+						set.Add(objectClass)
+					}
+				}
+			},
+			"options": func(s *SudoRole, v interface{}) {
+				set := v.(*schema.Set)
+				options := make([]string, 0)
+				for _, e := range set.List() {
+					options = append(options, e.(string))
+				}
+				s.Options = options
+			},
+			"order": func(s *SudoRole, v interface{}) { s.Order = v.(int) },
+			"path":  func(s *SudoRole, v interface{}) { s.Path = v.(string) },
+			"run_as_groups": func(s *SudoRole, v interface{}) {
+				set := v.(*schema.Set)
+				runAsGroups := make([]string, 0)
+				for _, e := range set.List() {
+					runAsGroups = append(runAsGroups, e.(string))
+				}
+				s.RunAsGroups = runAsGroups
+			},
+			"run_as_users": func(s *SudoRole, v interface{}) {
+				set := v.(*schema.Set)
+				runAsUsers := make([]string, 0)
+				for _, e := range set.List() {
+					runAsUsers = append(runAsUsers, e.(string))
+				}
+				s.RunAsUsers = runAsUsers
+			},
+			"users": func(s *SudoRole, v interface{}) {
+				set := v.(*schema.Set)
+				users := make([]string, 0)
+				for _, e := range set.List() {
+					users = append(users, e.(string))
+				}
+				s.Users = users
+			},
+		}
+		for property, fn := range properties {
+			newVal := d.Get(property)
+			fn(newSudoRole, newVal)
+			if d.HasChange(property) {
+				oldVal, _ := d.GetChange(property)
+				fn(oldSudoRole, oldVal)
+			} else {
+				fn(oldSudoRole, newVal)
+			}
+		}
+	}
+	return
+}
+
+func resourceLdapSudoRoleTimeDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
+	oldTime, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
@@ -0,0 +1,54 @@
+package ldap
+
+import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"testing"
+)
+
+func TestAccLdapSudoRole_basic(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "cn=web-admins,dc=example,dc=com"
+	config := func(commands string) string {
+		return testAccProviderConfig(server) + `
+resource "ldap_sudo_role" "web_admins" {
+  cn           = "web-admins"
+  path         = "dc=example,dc=com"
+  users        = ["%webadmins"]
+  hosts        = ["ALL"]
+  commands     = [` + commands + `]
+  run_as_users = ["root"]
+  options      = ["!authenticate"]
+  order        = 10
+  not_after    = "2030-01-01T00:00:00Z"
+}
+`
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`"/usr/bin/systemctl restart nginx"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_sudo_role.web_admins", "id", dn),
+					testAccCheckAttribute(server, dn, "objectClass", "top", "sudoRole"),
+					testAccCheckAttribute(server, dn, "sudoUser", "%webadmins"),
+					testAccCheckAttribute(server, dn, "sudoCommand", "/usr/bin/systemctl restart nginx"),
+					testAccCheckAttribute(server, dn, "sudoOrder", "10"),
+					testAccCheckAttribute(server, dn, "sudoNotAfter", "20300101000000Z"),
+				),
+			},
+			{
+				Config: config(`"/usr/bin/systemctl restart nginx", "/usr/bin/systemctl reload nginx"`),
+				Check:  testAccCheckAttribute(server, dn, "sudoCommand", "/usr/bin/systemctl restart nginx", "/usr/bin/systemctl reload nginx"),
+			},
+			{
+				Config:            config(`"/usr/bin/systemctl restart nginx", "/usr/bin/systemctl reload nginx"`),
+				ResourceName:      "ldap_sudo_role.web_admins",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
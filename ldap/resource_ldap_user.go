@@ -1,22 +1,35 @@
 package ldap
 
 import (
-	"errors"
+	"fmt"
 	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	"strings"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
+// userRDNArguments maps the attributes that may name a user to the arguments holding their values.
+var userRDNArguments = map[string]string{
+	"cn":             "cn",
+	"mail":           "email_address",
+	"sAMAccountName": "sam_account_name",
+	"uid":            "uid",
+}
+
 func resourceLdapUser() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		Create: resourceLdapUserCreate,
 		Read:   resourceLdapUserRead,
 		Update: resourceLdapUserUpdate,
 		Delete: resourceLdapUserDelete,
-		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWriteTimeout),
+			Update: schema.DefaultTimeout(defaultWriteTimeout),
+			Delete: schema.DefaultTimeout(defaultWriteTimeout),
 		},
+		Importer: importByIdentifier("(|(objectClass=person)(objectClass=posixAccount))(!(objectClass=computer))", "sAMAccountName", "uid", "objectGUID", "mail"),
 		Schema: map[string]*schema.Schema{
+			"attributes": extraAttributesSchema(),
 			"city": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -28,11 +41,21 @@ func resourceLdapUser() *schema.Resource {
 				Description: "The name that represents the object. Used to perform searches",
 				ForceNew:    true,
 			},
+			"company": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the user's company.",
+			},
 			"country": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "Specifies the country or region code.",
 			},
+			"department": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the user's department.",
+			},
 			"description": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -48,6 +71,16 @@ func resourceLdapUser() *schema.Resource {
 				Optional:    true,
 				Description: "Specifies the user's e-mail address.",
 			},
+			"employee_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the user's employee ID.",
+			},
+			"employee_number": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the user's employee number.",
+			},
 			"gid_number": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -63,6 +96,30 @@ func resourceLdapUser() *schema.Resource {
 				Optional:    true,
 				Description: "The home directory for the account.",
 			},
+			"ignore_attribute_changes": ignoreAttributeChangesSchema(),
+			"initials": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the initials that represent part of a user's name.",
+			},
+			"jpeg_photo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Specifies a base64-encoded JPEG photograph of the user.",
+				ValidateFunc: validation.StringIsBase64,
+			},
+			"managed_attributes": managedAttributesSchema(),
+			"manager": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Specifies the distinguished name of the user's manager.",
+				ValidateFunc: internal.DistinguishedName(),
+			},
+			"mobile_phone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the user's mobile phone number.",
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -78,17 +135,27 @@ func resourceLdapUser() *schema.Resource {
 				Set:         schema.HashString,
 				Description: "The list of classes from which this object is derived.",
 			},
+			"office": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the location of the user's office or place of business.",
+			},
+			"office_phone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the user's office telephone number.",
+			},
 			"path": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "Specifies the X.500 path of the OU or container where the new object is created.",
-				ForceNew:    true,
+				Description: "Specifies the X.500 path of the OU or container where the new object is created, relative to the provider's base DN unless fully qualified.",
 			},
 			"postal_code": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "Specifies the postal code or zip code.",
 			},
+			"rdn_attribute": rdnAttributeSchema(userRDNArguments),
 			"sam_account_name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -101,6 +168,16 @@ func resourceLdapUser() *schema.Resource {
 				Computed:    true,
 				Description: "Specifies the Security Account Manager (SAM) account type of the user.",
 			},
+			"ssh_public_keys": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: internal.SSHPublicKey(),
+				},
+				Set:         schema.HashString,
+				Description: fmt.Sprintf("Specifies the SSH public keys of the user. Adds the \"%s\" object class when present.", LDAP_PUBLIC_KEY),
+			},
 			"street_address": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -116,6 +193,17 @@ func resourceLdapUser() *schema.Resource {
 				Optional:    true,
 				Description: "Specifies the user's last name or surname.",
 			},
+			"thumbnail_photo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Specifies a base64-encoded thumbnail photograph of the user.",
+				ValidateFunc: validation.StringIsBase64,
+			},
+			"title": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the user's title.",
+			},
 			"uid": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -133,50 +221,66 @@ func resourceLdapUser() *schema.Resource {
 			},
 		},
 	}
+	resource.CustomizeDiff = customdiff.All(
+		pathCustomizeDiff,
+		relativeDNCustomizeDiff(userRDNArguments),
+		extraAttributesCustomizeDiff(&User{Flavor: FLAVOR_ACTIVE_DIRECTORY}),
+		activeDirectoryCustomizeDiff("company", "department", "employee_id", "name", "sam_account_name", "sam_account_type", "thumbnail_photo", "user_principal_name"),
+		resourceLdapUserObjectClassCustomizeDiff,
+		subschemaCustomizeDiff(resource.Schema, func(d resourceData, client *Client) (Object, error) {
+			_, obj, err := resourceLdapUserUnmarshal(d, client)
+			return obj, err
+		}),
+	)
+	resource.SchemaVersion = 1
+	resource.StateUpgraders = []schema.StateUpgrader{rdnAttributeStateUpgrader(resource, userRDNArguments)}
+	return resource
 }
 
 func resourceLdapUserCreate(d *schema.ResourceData, m interface{}) error {
-	_, u, err := resourceLdapUserUnmarshal(d)
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutCreate)).Pin()
+	defer client.Close()
+	_, u, err := resourceLdapUserUnmarshal(d, client)
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
 	if err := client.Add(u); err != nil {
 		return err
 	}
-	return resourceLdapUserRead(d, m)
+	return resourceLdapUserRead(d, client)
 }
 
 func resourceLdapUserRead(d *schema.ResourceData, m interface{}) error {
-	_, u, err := resourceLdapUserUnmarshal(d)
+	client := m.(*Client)
+	_, u, err := resourceLdapUserUnmarshal(d, client)
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
-	if err := client.Search(u); err != nil {
+	if err := client.Search(newAttributeFilter(u, d)); err != nil {
 		return err
 	}
 	return resourceLdapUserMarshal(u, d)
 }
 
 func resourceLdapUserUpdate(d *schema.ResourceData, m interface{}) error {
-	oldUser, newUser, err := resourceLdapUserUnmarshal(d)
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutUpdate)).Pin()
+	defer client.Close()
+	oldUser, newUser, err := resourceLdapUserUnmarshal(d, client)
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
 	if err := client.Modify(oldUser, newUser); err != nil {
 		return err
 	}
-	return resourceLdapUserRead(d, m)
+	return resourceLdapUserRead(d, client)
 }
 
 func resourceLdapUserDelete(d *schema.ResourceData, m interface{}) error {
-	_, u, err := resourceLdapUserUnmarshal(d)
+	client := m.(*Client).WithTimeout(d.Timeout(schema.TimeoutDelete))
+	_, u, err := resourceLdapUserUnmarshal(d, client)
 	if err != nil {
 		return err
 	}
-	client := m.(*Client)
 	if err := client.Delete(u); err != nil {
 		return err
 	}
@@ -187,55 +291,78 @@ func resourceLdapUserMarshal(u *User, d *schema.ResourceData) error {
 	if d.Id() != u.DN {
 		d.SetId(u.DN)
 	}
+	d.Set("attributes", flattenExtraAttributes(u.ExtraAttributes))
 	d.Set("city", u.City)
 	d.Set("cn", u.CommonName)
+	d.Set("company", u.Company)
 	d.Set("country", u.Country)
+	d.Set("department", u.Department)
 	d.Set("description", u.Description)
 	d.Set("display_name", u.DisplayName)
 	d.Set("email_address", u.EmailAddress)
+	d.Set("employee_id", u.EmployeeID)
+	d.Set("employee_number", u.EmployeeNumber)
 	d.Set("gid_number", u.GidNumber)
 	d.Set("given_name", u.GivenName)
 	d.Set("home_directory", u.HomeDirectory)
+	d.Set("initials", u.Initials)
+	d.Set("jpeg_photo", u.JpegPhoto)
+	d.Set("manager", u.Manager)
+	d.Set("mobile_phone", u.MobilePhone)
 	d.Set("name", u.Name)
 	d.Set("object_class", u.ObjectClass)
+	d.Set("office", u.Office)
+	d.Set("office_phone", u.OfficePhone)
 	d.Set("path", u.Path)
 	d.Set("postal_code", u.PostalCode)
+	d.Set("rdn_attribute", u.RDNAttribute)
 	d.Set("sam_account_name", u.SamAccountName)
 	d.Set("sam_account_type", u.SamAccountType)
+	d.Set("ssh_public_keys", u.SshPublicKeys)
 	d.Set("street_address", u.StreetAddress)
 	d.Set("state", u.State)
 	d.Set("surname", u.Surname)
+	d.Set("thumbnail_photo", u.ThumbnailPhoto)
+	d.Set("title", u.Title)
 	d.Set("uid", u.Uid)
 	d.Set("uid_number", u.UidNumber)
 	d.Set("user_principal_name", u.UserPrincipalName)
 	return nil
 }
 
-func resourceLdapUserUnmarshal(d *schema.ResourceData) (oldUser *User, newUser *User, err error) {
-	newUser = &User{DN: d.Id()}
-	oldUser = &User{DN: d.Id()}
+func resourceLdapUserUnmarshal(d resourceData, client *Client) (oldUser *User, newUser *User, err error) {
+	newUser = &User{DN: d.Id(), Flavor: client.Flavor}
+	oldUser = &User{DN: d.Id(), Flavor: client.Flavor}
 	if _, ok := d.GetOk("path"); !ok { // Not present in import
 		rdn, path, err := internal.ParseDN(d.Id())
 		if err != nil {
 			return oldUser, newUser, err
 		}
-		if !strings.HasPrefix(strings.ToLower(rdn), "cn=") {
-			return nil, nil, errors.New("invalid distinguished name; expected prefix \"cn=\"")
+		if err := newUser.setRelativeDN(rdn); err != nil {
+			return nil, nil, err
 		}
-		newUser.CommonName = rdn[3:]
 		newUser.Path = path
 	} else {
 		properties := map[string]func(*User, interface{}){
-			"city":           func(u *User, v interface{}) { u.City = v.(string) },
-			"cn":             func(u *User, v interface{}) { u.CommonName = v.(string) },
-			"country":        func(u *User, v interface{}) { u.Country = v.(string) },
-			"description":    func(u *User, v interface{}) { u.Description = v.(string) },
-			"display_name":   func(u *User, v interface{}) { u.DisplayName = v.(string) },
-			"email_address":  func(u *User, v interface{}) { u.EmailAddress = v.(string) },
-			"gid_number":     func(u *User, v interface{}) { u.GidNumber = v.(int) },
-			"given_name":     func(u *User, v interface{}) { u.GivenName = v.(string) },
-			"home_directory": func(u *User, v interface{}) { u.HomeDirectory = v.(string) },
-			"name":           func(u *User, v interface{}) { u.Name = v.(string) },
+			"attributes":      func(u *User, v interface{}) { u.ExtraAttributes = expandExtraAttributes(v) },
+			"city":            func(u *User, v interface{}) { u.City = v.(string) },
+			"cn":              func(u *User, v interface{}) { u.CommonName = v.(string) },
+			"company":         func(u *User, v interface{}) { u.Company = v.(string) },
+			"country":         func(u *User, v interface{}) { u.Country = v.(string) },
+			"department":      func(u *User, v interface{}) { u.Department = v.(string) },
+			"description":     func(u *User, v interface{}) { u.Description = v.(string) },
+			"display_name":    func(u *User, v interface{}) { u.DisplayName = v.(string) },
+			"email_address":   func(u *User, v interface{}) { u.EmailAddress = v.(string) },
+			"employee_id":     func(u *User, v interface{}) { u.EmployeeID = v.(string) },
+			"employee_number": func(u *User, v interface{}) { u.EmployeeNumber = v.(string) },
+			"gid_number":      func(u *User, v interface{}) { u.GidNumber = v.(int) },
+			"given_name":      func(u *User, v interface{}) { u.GivenName = v.(string) },
+			"home_directory":  func(u *User, v interface{}) { u.HomeDirectory = v.(string) },
+			"initials":        func(u *User, v interface{}) { u.Initials = v.(string) },
+			"jpeg_photo":      func(u *User, v interface{}) { u.JpegPhoto = v.(string) },
+			"manager":         func(u *User, v interface{}) { u.Manager = v.(string) },
+			"mobile_phone":    func(u *User, v interface{}) { u.MobilePhone = v.(string) },
+			"name":            func(u *User, v interface{}) { u.Name = v.(string) },
 			"object_class": func(u *User, v interface{}) {
 				set := v.(*schema.Set)
 				if set.Len() > 0 {
@@ -245,19 +372,32 @@ func resourceLdapUserUnmarshal(d *schema.ResourceData) (oldUser *User, newUser *
 					}
 					u.ObjectClass = objectClass
 				} else {
-					u.ObjectClass = []string{top, PERSON, ORGANIZATIONAL_PERSON, USER}
+					u.ObjectClass = userDefaultObjectClass(client.Flavor)
 					for _, objectClass := range u.ObjectClass {
 						set.Add(objectClass)
 					}
 				}
 			},
-			"path":                func(u *User, v interface{}) { u.Path = v.(string) },
-			"postal_code":         func(u *User, v interface{}) { u.PostalCode = v.(string) },
-			"sam_account_name":    func(u *User, v interface{}) { u.SamAccountName = v.(string) },
-			"sam_account_type":    func(u *User, v interface{}) { u.SamAccountType = v.(string) },
+			"office":           func(u *User, v interface{}) { u.Office = v.(string) },
+			"office_phone":     func(u *User, v interface{}) { u.OfficePhone = v.(string) },
+			"path":             func(u *User, v interface{}) { u.Path = v.(string) },
+			"postal_code":      func(u *User, v interface{}) { u.PostalCode = v.(string) },
+			"rdn_attribute":    func(u *User, v interface{}) { u.RDNAttribute = v.(string) },
+			"sam_account_name": func(u *User, v interface{}) { u.SamAccountName = v.(string) },
+			"sam_account_type": func(u *User, v interface{}) { u.SamAccountType = v.(string) },
+			"ssh_public_keys": func(u *User, v interface{}) {
+				set := v.(*schema.Set)
+				sshPublicKeys := make([]string, 0)
+				for _, k := range set.List() {
+					sshPublicKeys = append(sshPublicKeys, k.(string))
+				}
+				u.SshPublicKeys = sshPublicKeys
+			},
 			"street_address":      func(u *User, v interface{}) { u.StreetAddress = v.(string) },
 			"state":               func(u *User, v interface{}) { u.State = v.(string) },
 			"surname":             func(u *User, v interface{}) { u.Surname = v.(string) },
+			"thumbnail_photo":     func(u *User, v interface{}) { u.ThumbnailPhoto = v.(string) },
+			"title":               func(u *User, v interface{}) { u.Title = v.(string) },
 			"uid":                 func(u *User, v interface{}) { u.Uid = v.(string) },
 			"uid_number":          func(u *User, v interface{}) { u.UidNumber = v.(int) },
 			"user_principal_name": func(u *User, v interface{}) { u.UserPrincipalName = v.(string) },
@@ -272,6 +412,35 @@ func resourceLdapUserUnmarshal(d *schema.ResourceData) (oldUser *User, newUser *
 				fn(oldUser, newVal)
 			}
 		}
+		if newUser.RDNAttribute == "" && d.Id() != "" { // State written before rdn_attribute existed
+			newUser.RDNAttribute = relativeDNAttribute(d.Id(), userRDNArguments)
+			oldUser.RDNAttribute = newUser.RDNAttribute
+		}
 	}
 	return
 }
+
+// resourceLdapUserObjectClassCustomizeDiff plans the object class that sshPublicKey requires, which is
+// added to the configured object classes when they lack it.
+func resourceLdapUserObjectClassCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	keys, ok := d.Get("ssh_public_keys").(*schema.Set)
+	if !ok || keys.Len() == 0 || !d.NewValueKnown("object_class") {
+		return nil
+	}
+	objectClass := make([]string, 0)
+	for _, c := range d.Get("object_class").(*schema.Set).List() {
+		objectClass = append(objectClass, c.(string))
+	}
+	if len(objectClass) == 0 || containsFold(objectClass, LDAP_PUBLIC_KEY) {
+		return nil
+	}
+	return d.SetNew("object_class", append(objectClass, LDAP_PUBLIC_KEY))
+}
+
+// userDefaultObjectClass returns the object classes of a user whose object_class is not configured.
+func userDefaultObjectClass(flavor string) []string {
+	if flavor == FLAVOR_ACTIVE_DIRECTORY {
+		return []string{top, PERSON, ORGANIZATIONAL_PERSON, USER}
+	}
+	return []string{top, PERSON, ORGANIZATIONAL_PERSON, INET_ORG_PERSON}
+}
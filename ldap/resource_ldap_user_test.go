@@ -0,0 +1,172 @@
+package ldap
+
+import (
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/ldaptest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"regexp"
+	"testing"
+)
+
+func TestAccLdapUser_basic(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "cn=Alice Smith,dc=example,dc=com"
+	config := func(email string) string {
+		return testAccProviderConfig(server) + `
+resource "ldap_user" "alice" {
+  cn            = "Alice Smith"
+  path          = "dc=example,dc=com"
+  given_name    = "Alice"
+  surname       = "Smith"
+  uid           = "alice"
+  email_address = "` + email + `"
+}
+`
+	}
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: config("alice@example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_user.alice", "id", dn),
+					resource.TestCheckResourceAttr("ldap_user.alice", "email_address", "alice@example.com"),
+					testAccCheckAttribute(server, dn, "sn", "Smith"),
+					testAccCheckAttribute(server, dn, "givenName", "Alice"),
+					testAccCheckAttribute(server, dn, "uid", "alice"),
+					testAccCheckAttribute(server, dn, "mail", "alice@example.com"),
+				),
+			},
+			{
+				Config: config("asmith@example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_user.alice", "email_address", "asmith@example.com"),
+					testAccCheckAttribute(server, dn, "mail", "asmith@example.com"),
+				),
+			},
+			{
+				Config:            config("asmith@example.com"),
+				ResourceName:      "ldap_user.alice",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccLdapUser_rdnAttribute(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "uid=bob,dc=example,dc=com"
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_user" "bob" {
+  cn            = "Bob Jones"
+  path          = "dc=example,dc=com"
+  surname       = "Jones"
+  uid           = "bob"
+  rdn_attribute = "uid"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_user.bob", "id", dn),
+					testAccCheckAttribute(server, dn, "cn", "Bob Jones"),
+				),
+			},
+		},
+	})
+}
+
+// The ldapPublicKey object class added for SSH public keys must be planned, so that the configured object
+// classes do not differ from those read back.
+func TestAccLdapUser_sshPublicKeys(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "cn=Carol White,dc=example,dc=com"
+	key := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8g carol@example.com"
+	resource.Test(t, resource.TestCase{
+		Providers:    testAccProviders(),
+		CheckDestroy: testAccCheckDestroyed(server, dn),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_user" "carol" {
+  cn              = "Carol White"
+  path            = "dc=example,dc=com"
+  surname         = "White"
+  object_class    = ["top", "person", "organizationalPerson", "inetOrgPerson"]
+  ssh_public_keys = ["` + key + `"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ldap_user.carol", "object_class.#", "5"),
+					testAccCheckAttribute(server, dn, "objectClass", "top", "person", "organizationalPerson", "inetOrgPerson", "ldapPublicKey"),
+					testAccCheckAttribute(server, dn, "sshPublicKey", key),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLdapUser_activeDirectoryArguments(t *testing.T) {
+	// Rejected during plan rather than silently not written
+	server := testAccServer(t, ldaptest.Config{})
+	resource.Test(t, resource.TestCase{
+		Providers: testAccProviders(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `
+resource "ldap_user" "dave" {
+  cn               = "Dave Brown"
+  path             = "dc=example,dc=com"
+  surname          = "Brown"
+  sam_account_name = "dave"
+}
+`,
+				ExpectError: regexp.MustCompile(`sam_account_name is only supported by Active Directory, but the server flavor is "openldap"`),
+			},
+		},
+	})
+}
+
+// State written before rdn_attribute existed must refresh to the naming attribute of the DN, rather than
+// planning to replace the user.
+func TestLdapUser_upgradeRDNAttribute(t *testing.T) {
+	server := testAccServer(t, ldaptest.Config{})
+	dn := "cn=Alice Smith,dc=example,dc=com"
+	server.Put(dn, map[string][]string{
+		"objectClass": {"top", "person", "organizationalPerson", "inetOrgPerson"},
+		"cn":          {"Alice Smith"},
+		"sn":          {"Smith"},
+	})
+	client := testAccClient(t, server)
+	r := resourceLdapUser()
+	d := r.Data(&terraform.InstanceState{ID: dn, Attributes: map[string]string{
+		"id":             dn,
+		"cn":             "Alice Smith",
+		"path":           "dc=example,dc=com",
+		"surname":        "Smith",
+		"object_class.#": "4",
+	}})
+	if err := resourceLdapUserRead(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if actual := d.Get("rdn_attribute"); actual != "cn" {
+		t.Fatalf("rdn_attribute = %q, expected \"cn\"", actual)
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"cn":      "Alice Smith",
+		"path":    "dc=example,dc=com",
+		"surname": "Smith",
+	})
+	diff, err := r.Diff(d.State(), config, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.RequiresNew() {
+		t.Errorf("diff replaces the user: %v", diff)
+	}
+}
@@ -0,0 +1,26 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ServicePrincipalName is a single servicePrincipalName value registered on an account.
+type ServicePrincipalName struct {
+	Account string
+	Value   string
+}
+
+func (spn *ServicePrincipalName) GetID() string {
+	return spn.Account + "|" + spn.Value
+}
+
+func (spn *ServicePrincipalName) SetID(id string) error {
+	i := strings.LastIndex(id, "|")
+	if i < 1 || i == len(id)-1 {
+		return fmt.Errorf("invalid service principal name ID %q; expected \"<account DN>|<SPN>\"", id)
+	}
+	spn.Account = id[:i]
+	spn.Value = id[i+1:]
+	return nil
+}
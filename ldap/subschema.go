@@ -0,0 +1,233 @@
+package ldap
+
+import (
+	"fmt"
+	"github.com/etacticsinc/terraform-provider-ldap/ldap/internal"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"log"
+	"sort"
+	"strings"
+)
+
+const (
+	EXTENSIBLE_OBJECT = "extensibleObject"
+)
+
+// Subschema holds the object classes, attribute types and DIT content rules published by the server.
+type Subschema struct {
+	attributeTypes map[string]*attributeType
+	objectClasses  map[string]*objectClassType
+	contentRules   map[string]*contentRule
+}
+
+type attributeType struct {
+	OID                string
+	Names              []string
+	NoUserModification bool
+}
+
+type objectClassType struct {
+	OID       string
+	Names     []string
+	Superiors []string
+	Must      []string
+	May       []string
+}
+
+// contentRule describes the auxiliary classes and additional attributes permitted on entries of
+// a structural class, which Active Directory uses instead of listing them on the class itself.
+type contentRule struct {
+	OID  string
+	Aux  []string
+	Must []string
+	May  []string
+	Not  []string
+}
+
+// NewSubschema parses the objectClasses, attributeTypes and dITContentRules values of a subschema entry.
+func NewSubschema(attributes Attributes) (*Subschema, error) {
+	s := &Subschema{
+		attributeTypes: make(map[string]*attributeType),
+		objectClasses:  make(map[string]*objectClassType),
+		contentRules:   make(map[string]*contentRule),
+	}
+	for _, description := range attributes.Get("attributeTypes") {
+		oid, fields, err := internal.ParseSchemaDescription(description)
+		if err != nil {
+			return nil, err
+		}
+		_, noUserModification := fields["NO-USER-MODIFICATION"]
+		t := &attributeType{OID: oid, Names: fields["NAME"], NoUserModification: noUserModification}
+		s.attributeTypes[strings.ToLower(oid)] = t
+		for _, name := range t.Names {
+			s.attributeTypes[strings.ToLower(name)] = t
+		}
+	}
+	for _, description := range attributes.Get("objectClasses") {
+		oid, fields, err := internal.ParseSchemaDescription(description)
+		if err != nil {
+			return nil, err
+		}
+		c := &objectClassType{OID: oid, Names: fields["NAME"], Superiors: fields["SUP"], Must: fields["MUST"], May: fields["MAY"]}
+		s.objectClasses[strings.ToLower(oid)] = c
+		for _, name := range c.Names {
+			s.objectClasses[strings.ToLower(name)] = c
+		}
+	}
+	for _, description := range attributes.Get("dITContentRules") {
+		oid, fields, err := internal.ParseSchemaDescription(description)
+		if err != nil {
+			return nil, err
+		}
+		s.contentRules[strings.ToLower(oid)] = &contentRule{OID: oid, Aux: fields["AUX"], Must: fields["MUST"], May: fields["MAY"], Not: fields["NOT"]}
+	}
+	return s, nil
+}
+
+// Validate reports unknown object classes, missing required attributes and attributes not
+// allowed by any object class of attributes. Required attributes in defaulted are assumed to be
+// supplied by the server.
+func (s *Subschema) Validate(attributes Attributes, defaulted []string, checkMust bool) []error {
+	errs := make([]error, 0)
+	classes := make(map[string]*objectClassType)
+	for _, name := range attributes.Get("objectClass") {
+		c, ok := s.objectClasses[strings.ToLower(name)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown object class %q", name))
+			continue
+		}
+		s.addClass(classes, c)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, c := range classes {
+		if rule, ok := s.contentRules[strings.ToLower(c.OID)]; ok {
+			for _, aux := range rule.Aux {
+				if auxClass, ok := s.objectClasses[strings.ToLower(aux)]; ok {
+					s.addClass(classes, auxClass)
+				}
+			}
+		}
+	}
+	must := make(map[string]string)
+	allowed := make(map[string]bool)
+	precluded := make([]string, 0)
+	extensible := false
+	for _, c := range classes {
+		for _, name := range c.Names {
+			extensible = extensible || strings.EqualFold(name, EXTENSIBLE_OBJECT)
+		}
+		for _, name := range c.Must {
+			must[s.attributeID(name)] = name
+			allowed[s.attributeID(name)] = true
+		}
+		for _, name := range c.May {
+			allowed[s.attributeID(name)] = true
+		}
+		if rule, ok := s.contentRules[strings.ToLower(c.OID)]; ok {
+			for _, name := range rule.Must {
+				must[s.attributeID(name)] = name
+				allowed[s.attributeID(name)] = true
+			}
+			for _, name := range rule.May {
+				allowed[s.attributeID(name)] = true
+			}
+			for _, name := range rule.Not {
+				precluded = append(precluded, s.attributeID(name))
+			}
+		}
+	}
+	for _, id := range precluded { // Content rules preclude attributes allowed by any of the classes
+		delete(allowed, id)
+	}
+	present := make(map[string]bool)
+	keys := attributes.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !attributes.HasValue(key) {
+			continue
+		}
+		id := s.attributeID(key)
+		present[id] = true
+		if _, ok := s.attributeTypes[id]; !ok {
+			errs = append(errs, fmt.Errorf("unknown attribute %q", key))
+		} else if !allowed[id] && !extensible {
+			errs = append(errs, fmt.Errorf("attribute %q is not allowed by object classes %v", key, attributes.Get("objectClass")))
+		}
+	}
+	if checkMust {
+		missing := make([]string, 0)
+		for id, name := range must {
+			if t, ok := s.attributeTypes[id]; present[id] || (ok && t.NoUserModification) || containsFold(defaulted, name) {
+				continue
+			}
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+		for _, name := range missing {
+			errs = append(errs, fmt.Errorf("attribute %q is required by object classes %v", name, attributes.Get("objectClass")))
+		}
+	}
+	return errs
+}
+
+func (s *Subschema) addClass(classes map[string]*objectClassType, c *objectClassType) {
+	if _, ok := classes[c.OID]; ok {
+		return
+	}
+	classes[c.OID] = c
+	for _, superior := range c.Superiors {
+		if sup, ok := s.objectClasses[strings.ToLower(superior)]; ok {
+			s.addClass(classes, sup)
+		}
+	}
+}
+
+// attributeID returns the OID of an attribute name, ignoring attribute options such as ";binary".
+func (s *Subschema) attributeID(name string) string {
+	name = strings.ToLower(strings.SplitN(name, ";", 2)[0])
+	if t, ok := s.attributeTypes[name]; ok {
+		return strings.ToLower(t.OID)
+	}
+	return name
+}
+
+// subschemaCustomizeDiff validates the object unmarshalled from a planned create or update against
+// the server's subschema, so that schema violations are reported during plan.
+func subschemaCustomizeDiff(resourceSchema map[string]*schema.Schema, unmarshal func(resourceData, *Client) (Object, error)) schema.CustomizeDiffFunc {
+	return func(d *schema.ResourceDiff, m interface{}) error {
+		client, ok := m.(*Client)
+		if !ok || !client.ValidateSchema {
+			return nil
+		}
+		if d.Id() != "" && len(d.GetChangedKeysPrefix("")) == 0 {
+			return nil
+		}
+		obj, err := unmarshal(d, client)
+		if err != nil {
+			return err
+		}
+		subschema, err := client.Subschema()
+		if err != nil {
+			log.Printf("[WARN] Skipping schema validation: %v", err)
+			return nil
+		}
+		// Required attributes may be supplied by values that are not known until apply
+		checkMust := true
+		for key, s := range resourceSchema {
+			if !s.Computed && !d.NewValueKnown(key) {
+				checkMust = false
+			}
+		}
+		errs := subschema.Validate(obj.GetAttributes(), serverDefaultedAttributes(client.Flavor), checkMust)
+		if len(errs) == 0 {
+			return nil
+		}
+		messages := make([]string, len(errs))
+		for i, err := range errs {
+			messages[i] = err.Error()
+		}
+		return fmt.Errorf("%s,%s does not conform to the directory schema:\n  %s", obj.GetRelativeDN(), obj.GetPath(), strings.Join(messages, "\n  "))
+	}
+}
@@ -0,0 +1,143 @@
+package ldap
+
+import (
+	"strings"
+	"testing"
+)
+
+// testSubschema is an excerpt of the Active Directory schema, with inetOrgPerson and extensibleObject.
+func testSubschema(t *testing.T) *Subschema {
+	subschema, err := NewSubschema(Attributes{map[string][]string{
+		"attributeTypes": {
+			"( 2.5.4.0 NAME 'objectClass' )",
+			"( 2.5.4.3 NAME 'cn' )",
+			"( 2.5.4.4 NAME 'sn' )",
+			"( 2.5.4.13 NAME 'description' )",
+			"( 2.5.4.31 NAME 'member' )",
+			"( 0.9.2342.19200300.100.1.1 NAME 'uid' )",
+			"( 0.9.2342.19200300.100.1.3 NAME 'mail' )",
+			"( 1.2.840.113556.1.2.1 NAME 'instanceType' )",
+			"( 1.2.840.113556.1.2.281 NAME 'nTSecurityDescriptor' )",
+			"( 1.2.840.113556.1.4.782 NAME 'objectCategory' )",
+			"( 1.2.840.113556.1.4.146 NAME 'objectSid' )",
+			"( 1.2.840.113556.1.4.221 NAME 'sAMAccountName' )",
+			"( 1.2.840.113556.1.4.750 NAME 'groupType' )",
+			"( 1.2.840.113556.1.4.302 NAME 'sAMAccountType' )",
+			"( 2.5.18.1 NAME 'createTimestamp' NO-USER-MODIFICATION )",
+			"( 2.5.4.36 NAME 'userCertificate' )",
+		},
+		"objectClasses": {
+			"( 2.5.6.0 NAME 'top' ABSTRACT MUST objectClass MAY ( instanceType $ nTSecurityDescriptor $ objectCategory $ description ) )",
+			"( 2.5.6.6 NAME 'person' SUP top STRUCTURAL MUST ( sn $ cn ) MAY userCertificate )",
+			"( 2.16.840.1.113730.3.2.2 NAME 'inetOrgPerson' SUP person STRUCTURAL MAY ( uid $ mail ) )",
+			"( 1.2.840.113556.1.5.8 NAME 'group' SUP top STRUCTURAL MUST groupType MAY ( member $ cn $ createTimestamp ) )",
+			"( 1.2.840.113556.1.5.6 NAME 'securityPrincipal' SUP top AUXILIARY MUST ( objectSid $ sAMAccountName ) MAY sAMAccountType )",
+			"( 1.3.6.1.4.1.1466.101.120.111 NAME 'extensibleObject' SUP top AUXILIARY )",
+		},
+		"dITContentRules": {
+			"( 1.2.840.113556.1.5.8 NAME 'group' AUX securityPrincipal NOT description )",
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return subschema
+}
+
+func testValidateMessages(errs []error) string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// A group without a category and scope omits groupType, which Active Directory supplies.
+func TestSubschema_ValidateActiveDirectoryGroup(t *testing.T) {
+	subschema := testSubschema(t)
+	group := &Group{CommonName: "admins", SamAccountName: "admins", Flavor: FLAVOR_ACTIVE_DIRECTORY, ObjectClass: []string{"top", GROUP}}
+	if errs := subschema.Validate(group.GetAttributes(), serverDefaultedAttributes(FLAVOR_ACTIVE_DIRECTORY), true); len(errs) > 0 {
+		t.Errorf("Validate returned %s, expected no errors", testValidateMessages(errs))
+	}
+	errs := subschema.Validate(group.GetAttributes(), nil, true)
+	if messages := testValidateMessages(errs); !strings.Contains(messages, `"groupType" is required`) || !strings.Contains(messages, `"objectSid" is required`) {
+		t.Errorf("Validate returned %s, expected groupType and objectSid to be required", messages)
+	}
+	group.Members = []string{"cn=alice,dc=example,dc=com"}
+	group.GroupCategory, group.GroupScope = SECURITY, GLOBAL
+	if errs := subschema.Validate(group.GetAttributes(), serverDefaultedAttributes(FLAVOR_ACTIVE_DIRECTORY), true); len(errs) > 0 {
+		t.Errorf("Validate returned %s, expected no errors", testValidateMessages(errs))
+	}
+}
+
+func TestSubschema_Validate(t *testing.T) {
+	subschema := testSubschema(t)
+	cases := []struct {
+		name       string
+		attributes map[string][]string
+		checkMust  bool
+		expected   []string
+	}{
+		{
+			name:       "valid",
+			attributes: map[string][]string{"objectClass": {"top", "inetOrgPerson"}, "cn": {"Alice"}, "SN": {"Smith"}, "uid": {"alice"}},
+			checkMust:  true,
+		},
+		{
+			name:       "inherited and missing required attributes",
+			attributes: map[string][]string{"objectClass": {"inetOrgPerson"}, "mail": {"alice@example.com"}},
+			checkMust:  true,
+			expected:   []string{`attribute "cn" is required`, `attribute "sn" is required`},
+		},
+		{
+			name:       "required attributes unknown until apply",
+			attributes: map[string][]string{"objectClass": {"inetOrgPerson"}, "mail": {"alice@example.com"}},
+		},
+		{
+			name:       "unknown object class",
+			attributes: map[string][]string{"objectClass": {"top", "posixAccount"}, "cn": {"Alice"}},
+			checkMust:  true,
+			expected:   []string{`unknown object class "posixAccount"`},
+		},
+		{
+			name:       "unknown and disallowed attributes",
+			attributes: map[string][]string{"objectClass": {"person"}, "cn": {"Alice"}, "sn": {"Smith"}, "mail": {"alice@example.com"}, "shoeSize": {"9"}},
+			checkMust:  true,
+			expected:   []string{`attribute "mail" is not allowed`, `unknown attribute "shoeSize"`},
+		},
+		{
+			name:       "attribute options",
+			attributes: map[string][]string{"objectClass": {"person"}, "cn": {"Alice"}, "sn": {"Smith"}, "userCertificate;binary": {"..."}},
+			checkMust:  true,
+		},
+		{
+			name:       "extensibleObject allows any attribute",
+			attributes: map[string][]string{"objectClass": {"person", "extensibleObject"}, "cn": {"Alice"}, "sn": {"Smith"}, "mail": {"alice@example.com"}},
+			checkMust:  true,
+		},
+		{
+			name:       "content rule excludes an attribute",
+			attributes: map[string][]string{"objectClass": {"group"}, "groupType": {"2"}, "description": {"Staff"}, "objectSid": {"S-1"}, "sAMAccountName": {"staff"}},
+			checkMust:  true,
+			expected:   []string{`attribute "description" is not allowed`},
+		},
+		{
+			name:       "empty values are ignored",
+			attributes: map[string][]string{"objectClass": {"person"}, "cn": {"Alice"}, "sn": {"Smith"}, "mail": {""}},
+			checkMust:  true,
+		},
+	}
+	for _, c := range cases {
+		errs := subschema.Validate(Attributes{c.attributes}, nil, c.checkMust)
+		messages := testValidateMessages(errs)
+		if len(errs) != len(c.expected) {
+			t.Errorf("%s: Validate returned %q, expected %d errors", c.name, messages, len(c.expected))
+			continue
+		}
+		for _, expected := range c.expected {
+			if !strings.Contains(messages, expected) {
+				t.Errorf("%s: Validate returned %q, expected %q", c.name, messages, expected)
+			}
+		}
+	}
+}
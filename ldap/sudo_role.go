@@ -0,0 +1,113 @@
+package ldap
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	SUDO_ROLE = "sudoRole"
+	// sudoTimeLayout is the generalized time format of sudoNotBefore and sudoNotAfter
+	sudoTimeLayout = "20060102150405Z"
+)
+
+type SudoRole struct {
+	Commands        []string
+	CommonName      string
+	Description     string
+	DN              string
+	ExtraAttributes Attributes
+	Hosts           []string
+	NotAfter        string
+	NotBefore       string
+	ObjectClass     []string
+	Options         []string
+	Order           int
+	Path            string
+	RunAsGroups     []string
+	RunAsUsers      []string
+	Users           []string
+}
+
+func (s *SudoRole) GetAttributes() Attributes {
+	attributes := Attributes{map[string][]string{
+		"cn":             {s.CommonName},
+		"sudoCommand":    s.Commands,
+		"description":    {s.Description},
+		"sudoHost":       s.Hosts,
+		"sudoNotAfter":   {sudoGeneralizedTime(s.NotAfter)},
+		"sudoNotBefore":  {sudoGeneralizedTime(s.NotBefore)},
+		"objectClass":    s.ObjectClass,
+		"sudoOption":     s.Options,
+		"sudoOrder":      {""},
+		"sudoRunAsGroup": s.RunAsGroups,
+		"sudoRunAsUser":  s.RunAsUsers,
+		"sudoUser":       s.Users,
+	}}
+	if s.Order != 0 {
+		attributes.Map["sudoOrder"] = []string{strconv.Itoa(s.Order)}
+	}
+	attributes.Merge(s.ExtraAttributes)
+	return attributes
+}
+
+func (s *SudoRole) SetAttributes(attributes Attributes) {
+	s.Commands = attributes.Get("sudoCommand")
+	s.CommonName = attributes.GetFirst("cn")
+	s.Description = attributes.GetFirst("description")
+	s.ExtraAttributes = attributes.Select(s.ExtraAttributes.Keys())
+	s.Hosts = attributes.Get("sudoHost")
+	s.NotAfter = sudoRFC3339Time(attributes.GetFirst("sudoNotAfter"))
+	s.NotBefore = sudoRFC3339Time(attributes.GetFirst("sudoNotBefore"))
+	s.ObjectClass = attributes.Get("objectClass")
+	s.Options = attributes.Get("sudoOption")
+	if attributes.HasValue("sudoOrder") {
+		order, _ := strconv.Atoi(attributes.GetFirst("sudoOrder"))
+		s.Order = order
+	}
+	s.RunAsGroups = attributes.Get("sudoRunAsGroup")
+	s.RunAsUsers = attributes.Get("sudoRunAsUser")
+	s.Users = attributes.Get("sudoUser")
+}
+
+func (s *SudoRole) GetObjectClass() []string {
+	return s.ObjectClass
+}
+
+func (s *SudoRole) GetDN() string {
+	return s.DN
+}
+
+func (s *SudoRole) GetPath() string {
+	return s.Path
+}
+
+func (s *SudoRole) GetRelativeDN() string {
+	return "cn=" + s.CommonName
+}
+
+func (s *SudoRole) GetNamingAttribute() (string, string) {
+	return "cn", s.CommonName
+}
+
+func (s *SudoRole) SetDN(dn string) {
+	s.DN = dn
+}
+
+// sudoGeneralizedTime converts an RFC 3339 time to the generalized time stored in the directory.
+func sudoGeneralizedTime(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.UTC().Format(sudoTimeLayout)
+}
+
+// sudoRFC3339Time converts a generalized time stored in the directory to RFC 3339.
+func sudoRFC3339Time(value string) string {
+	t, err := time.Parse(sudoTimeLayout, value)
+	if err != nil {
+		return value
+	}
+	return t.Format(time.RFC3339)
+}
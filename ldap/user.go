@@ -1,6 +1,7 @@
 package ldap
 
 import (
+	"encoding/base64"
 	"fmt"
 	"strconv"
 )
@@ -9,6 +10,7 @@ const (
 	PERSON                  = "person"
 	ORGANIZATIONAL_PERSON   = "organizationalPerson"
 	INET_ORG_PERSON         = "inetOrgPerson"
+	LDAP_PUBLIC_KEY         = "ldapPublicKey"
 	POSIX_ACCOUNT           = "posixAccount"
 	SHADOW_ACCOUNT          = "shadowAccount"
 	USER                    = "user"
@@ -18,23 +20,39 @@ const (
 type User struct {
 	City              string
 	CommonName        string
+	Company           string
 	Country           string
+	Department        string
 	Description       string
 	DisplayName       string
 	DN                string
 	EmailAddress      string
+	EmployeeID        string
+	EmployeeNumber    string
+	ExtraAttributes   Attributes
+	Flavor            string
 	GidNumber         int
 	GivenName         string
 	HomeDirectory     string
+	Initials          string
+	JpegPhoto         string
+	Manager           string
+	MobilePhone       string
 	Name              string
 	ObjectClass       []string
+	Office            string
+	OfficePhone       string
 	Path              string
 	PostalCode        string
+	RDNAttribute      string
 	SamAccountName    string
 	SamAccountType    string
+	SshPublicKeys     []string
 	State             string
 	StreetAddress     string
 	Surname           string
+	ThumbnailPhoto    string
+	Title             string
 	Uid               string
 	UidNumber         int
 	UserPrincipalName string
@@ -42,45 +60,67 @@ type User struct {
 
 func (u *User) GetAttributes() Attributes {
 	m := map[string][]string{
-		"l":                 {u.City},
-		"cn":                {u.CommonName},
-		"c":                 {u.Country},
-		"description":       {u.Description},
-		"displayName":       {u.DisplayName},
-		"mail":              {u.EmailAddress},
-		"gidNumber":         {""},
-		"givenName":         {u.GivenName},
-		"homeDirectory":     {u.HomeDirectory},
-		"name":              {u.Name},
-		"objectClass":       u.ObjectClass,
-		"postalCode":        {u.PostalCode},
-		"sAMAccountName":    {u.SamAccountName},
-		"sAMAccountType":    {""},
-		"st":                {u.State},
-		"streetAddress":     {u.StreetAddress},
-		"sn":                {u.Surname},
-		"uid":               {u.Uid},
-		"uidNumber":         {""},
-		"userPrincipalName": {u.UserPrincipalName},
+		"l":                          {u.City},
+		"cn":                         {u.CommonName},
+		"c":                          {u.Country},
+		"description":                {u.Description},
+		"displayName":                {u.DisplayName},
+		"mail":                       {u.EmailAddress},
+		"employeeNumber":             {u.EmployeeNumber},
+		"gidNumber":                  {""},
+		"givenName":                  {u.GivenName},
+		"homeDirectory":              {u.HomeDirectory},
+		"initials":                   {u.Initials},
+		"jpegPhoto":                  {""},
+		"manager":                    {u.Manager},
+		"mobile":                     {u.MobilePhone},
+		"objectClass":                u.ObjectClass,
+		"physicalDeliveryOfficeName": {u.Office},
+		"telephoneNumber":            {u.OfficePhone},
+		"postalCode":                 {u.PostalCode},
+		"sshPublicKey":               u.SshPublicKeys,
+		"st":                         {u.State},
+		"streetAddress":              {u.StreetAddress},
+		"sn":                         {u.Surname},
+		"title":                      {u.Title},
+		"uid":                        {u.Uid},
+		"uidNumber":                  {""},
 	}
 	if u.GidNumber != 0 {
 		m["gidNumber"] = []string{strconv.Itoa(u.GidNumber)}
 	}
-	if u.SamAccountType == SAM_NORMAL_USER_ACCOUNT {
-		m["sAMAccountType"] = []string{fmt.Sprintf("%d", 0x30000000)}
+	// Photos are configured base64-encoded but stored as raw octet strings
+	if jpegPhoto, err := base64.StdEncoding.DecodeString(u.JpegPhoto); err == nil {
+		m["jpegPhoto"] = []string{string(jpegPhoto)}
+	}
+	// sshPublicKey is only permitted by the openssh-lpk auxiliary class
+	if len(u.SshPublicKeys) > 0 && !containsFold(u.ObjectClass, LDAP_PUBLIC_KEY) {
+		objectClass := make([]string, len(u.ObjectClass), len(u.ObjectClass)+1)
+		copy(objectClass, u.ObjectClass)
+		m["objectClass"] = append(objectClass, LDAP_PUBLIC_KEY)
 	}
 	if u.UidNumber != 0 {
 		m["uidNumber"] = []string{strconv.Itoa(u.UidNumber)}
 	}
-	return Attributes{m}
+	if u.Flavor == FLAVOR_ACTIVE_DIRECTORY { // Attributes only defined by the Active Directory schema
+		u.setActiveDirectoryAttributes(m)
+	}
+	attributes := Attributes{m}
+	attributes.Merge(u.ExtraAttributes)
+	return attributes
 }
 
 func (u *User) SetAttributes(attributes Attributes) {
 	u.City = attributes.GetFirst("l")
 	u.CommonName = attributes.GetFirst("cn")
+	u.Company = attributes.GetFirst("company")
 	u.Country = attributes.GetFirst("c")
+	u.Department = attributes.GetFirst("department")
 	u.Description = attributes.GetFirst("description")
 	u.DisplayName = attributes.GetFirst("displayName")
+	u.EmployeeID = attributes.GetFirst("employeeID")
+	u.EmployeeNumber = attributes.GetFirst("employeeNumber")
+	u.ExtraAttributes = attributes.Select(u.ExtraAttributes.Keys())
 	if attributes.HasValue("gidNumber") {
 		gidNumber, _ := strconv.Atoi(attributes.GetFirst("gidNumber"))
 		u.GidNumber = gidNumber
@@ -88,8 +128,14 @@ func (u *User) SetAttributes(attributes Attributes) {
 	u.EmailAddress = attributes.GetFirst("mail")
 	u.GivenName = attributes.GetFirst("givenName")
 	u.HomeDirectory = attributes.GetFirst("homeDirectory")
+	u.Initials = attributes.GetFirst("initials")
+	u.JpegPhoto = base64.StdEncoding.EncodeToString([]byte(attributes.GetFirst("jpegPhoto")))
+	u.Manager = attributes.GetFirst("manager")
+	u.MobilePhone = attributes.GetFirst("mobile")
 	u.Name = attributes.GetFirst("name")
 	u.ObjectClass = attributes.Get("objectClass")
+	u.Office = attributes.GetFirst("physicalDeliveryOfficeName")
+	u.OfficePhone = attributes.GetFirst("telephoneNumber")
 	u.PostalCode = attributes.GetFirst("postalCode")
 	u.SamAccountName = attributes.GetFirst("sAMAccountName")
 	if attributes.HasValue("sAMAccountType") {
@@ -98,9 +144,12 @@ func (u *User) SetAttributes(attributes Attributes) {
 			u.SamAccountType = SAM_NORMAL_USER_ACCOUNT
 		}
 	}
+	u.SshPublicKeys = attributes.Get("sshPublicKey")
 	u.State = attributes.GetFirst("st")
 	u.StreetAddress = attributes.GetFirst("streetAddress")
 	u.Surname = attributes.GetFirst("sn")
+	u.ThumbnailPhoto = base64.StdEncoding.EncodeToString([]byte(attributes.GetFirst("thumbnailPhoto")))
+	u.Title = attributes.GetFirst("title")
 	u.Uid = attributes.GetFirst("uid")
 	if attributes.HasValue("uidNumber") {
 		uidNumber, _ := strconv.Atoi(attributes.GetFirst("uidNumber"))
@@ -109,6 +158,23 @@ func (u *User) SetAttributes(attributes Attributes) {
 	u.UserPrincipalName = attributes.GetFirst("userPrincipalName")
 }
 
+func (u *User) setActiveDirectoryAttributes(m map[string][]string) {
+	m["company"] = []string{u.Company}
+	m["department"] = []string{u.Department}
+	m["employeeID"] = []string{u.EmployeeID}
+	m["name"] = []string{u.Name}
+	m["sAMAccountName"] = []string{u.SamAccountName}
+	m["sAMAccountType"] = []string{""}
+	m["thumbnailPhoto"] = []string{""}
+	m["userPrincipalName"] = []string{u.UserPrincipalName}
+	if u.SamAccountType == SAM_NORMAL_USER_ACCOUNT {
+		m["sAMAccountType"] = []string{fmt.Sprintf("%d", 0x30000000)}
+	}
+	if thumbnailPhoto, err := base64.StdEncoding.DecodeString(u.ThumbnailPhoto); err == nil {
+		m["thumbnailPhoto"] = []string{string(thumbnailPhoto)}
+	}
+}
+
 func (u *User) GetObjectClass() []string {
 	return u.ObjectClass
 }
@@ -122,7 +188,40 @@ func (u *User) GetPath() string {
 }
 
 func (u *User) GetRelativeDN() string {
-	return "cn=" + u.CommonName
+	attribute, value := u.GetNamingAttribute()
+	return attribute + "=" + value
+}
+
+func (u *User) GetNamingAttribute() (string, string) {
+	switch u.RDNAttribute {
+	case "mail":
+		return "mail", u.EmailAddress
+	case "sAMAccountName":
+		return "sAMAccountName", u.SamAccountName
+	case "uid":
+		return "uid", u.Uid
+	}
+	return "cn", u.CommonName
+}
+
+// setRelativeDN sets the naming attribute of the user and its value from rdn, e.g. "uid=jdoe".
+func (u *User) setRelativeDN(rdn string) error {
+	attribute, value, err := parseRelativeDN(rdn, rdnAttributes(userRDNArguments))
+	if err != nil {
+		return err
+	}
+	u.RDNAttribute = attribute
+	switch attribute {
+	case "mail":
+		u.EmailAddress = value
+	case "sAMAccountName":
+		u.SamAccountName = value
+	case "uid":
+		u.Uid = value
+	default:
+		u.CommonName = value
+	}
+	return nil
 }
 
 func (u *User) SetDN(dn string) {
@@ -0,0 +1,19 @@
+package ldap
+
+import (
+	"testing"
+)
+
+func TestUser_photos(t *testing.T) {
+	user := &User{CommonName: "Alice Smith", Flavor: FLAVOR_ACTIVE_DIRECTORY, JpegPhoto: "/9j/4AAQ", ThumbnailPhoto: "/9j/4AAQ"}
+	read := &User{}
+	read.SetAttributes(user.GetAttributes())
+	if read.JpegPhoto != user.JpegPhoto || read.ThumbnailPhoto != user.ThumbnailPhoto {
+		t.Errorf("photos read as %q and %q, expected %q", read.JpegPhoto, read.ThumbnailPhoto, user.JpegPhoto)
+	}
+	// Photos removed outside of Terraform are drift
+	read.SetAttributes(Attributes{map[string][]string{"cn": {"Alice Smith"}}})
+	if read.JpegPhoto != "" || read.ThumbnailPhoto != "" {
+		t.Errorf("photos read as %q and %q, expected them to be cleared", read.JpegPhoto, read.ThumbnailPhoto)
+	}
+}
@@ -1,15 +1,74 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"github.com/etacticsinc/terraform-provider-ldap/ldap"
 	"github.com/hashicorp/terraform-plugin-sdk/plugin"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"io"
+	"os"
+	"strings"
+	"time"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := generate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: func() terraform.ResourceProvider {
 			return ldap.Provider()
 		},
 	})
 }
+
+// generate writes Terraform configuration for the users, groups and organizational units beneath a base DN.
+func generate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	server := flags.String("server", os.Getenv("LDAP_SERVER"), "The LDAP server URL, e.g. ldap://corp.example.com.")
+	bindDN := flags.String("bind-dn", os.Getenv("LDAP_BIND_DN"), "The distinguished name to bind as.")
+	base := flags.String("base", "", "The distinguished name to search beneath. Defaults to the default naming context of the server.")
+	flavor := flags.String("flavor", ldap.FLAVOR_AUTO, "The directory server implementation.")
+	types := flags.String("types", "ldap_organizational_unit,ldap_group,ldap_user", "The comma-separated resource types to generate.")
+	out := flags.String("out", "", "The file to write to. Defaults to standard output.")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s generate [options]\n\nThe bind password is read from the LDAP_BIND_PASSWORD environment variable.\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *server == "" {
+		return fmt.Errorf("-server is required")
+	}
+	config := ldap.Config{
+		Server:         *server,
+		BindDN:         *bindDN,
+		BindPassword:   os.Getenv("LDAP_BIND_PASSWORD"),
+		ConnectTimeout: 30 * time.Second,
+		Flavor:         *flavor,
+		MaxRetries:     3,
+		ReadOnly:       true,
+		RequestTimeout: 60 * time.Second,
+	}
+	client, err := config.Client()
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	options := ldap.GenerateOptions{Base: *base, Types: strings.Split(*types, ",")}
+	return ldap.Generate(client.(*ldap.Client), options, w)
+}